@@ -0,0 +1,154 @@
+package part5
+
+import (
+	"sync"
+
+	"github.com/pascaldekloe/part5/info"
+)
+
+// PointCache retains the latest information per information object, in monitor
+// direction. The content survives reconnects, such that a user interface does
+// not blank out on a session Exit. Call MarkStale on session Exit instead, and
+// Update with each inbound ASDU, including the interrogation on reconnect. All
+// methods are safe for concurrent use.
+type PointCache[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr] struct {
+	mutex  sync.Mutex
+	points map[pointKey[Com, Obj]]*CachedPoint
+}
+
+type pointKey[Com info.ComAddr, Obj info.ObjAddr] struct {
+	com Com
+	obj Obj
+}
+
+// CachedPoint is an information object from a PointCache.
+type CachedPoint struct {
+	Type  info.TypeID
+	Cause info.Cause
+	// The information element(s), including any time tag, are conform
+	// Type. Stale entries have the NotTopical flag set, if the type has
+	// a quality descriptor.
+	Element []byte
+	// Stale is set by MarkStale, until the next update of the point.
+	Stale bool
+}
+
+// NewPointCache returns a new, empty cache.
+func NewPointCache[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr]() *PointCache[Orig, Com, Obj] {
+	return &PointCache[Orig, Com, Obj]{
+		points: make(map[pointKey[Com, Obj]]*CachedPoint),
+	}
+}
+
+// Update sets each information object in u. Types outside the monitor range
+// get ErrNotMonitor, and reserved types get ErrMonitorReserve.
+func (c *PointCache[Orig, Com, Obj]) Update(u info.DataUnit[Orig, Com, Obj]) error {
+	if u.Type == 0 || u.Type > 44 {
+		return ErrNotMonitor
+	}
+	elemSize, ok := info.ElemSize(u.Type)
+	if !ok {
+		return ErrMonitorReserve
+	}
+
+	if u.Enc.Count() == 0 {
+		return payloadErr(&u, errInfoSize)
+	}
+
+	// NOTE: Go can't get the array length from a generic as a constant yet.
+	var addr Obj
+
+	if u.Enc.AddrSeq() {
+		if !info.AllowsSequence(u.Type) {
+			// variable structure qualifier at offset 1
+			return info.DecodeError{Type: u.Type, Offset: 1, Reason: info.ErrAddrSeqType}
+		}
+		first, err := addrSeqStart(&u, elemSize)
+		if err != nil {
+			return err
+		}
+
+		c.mutex.Lock()
+		defer c.mutex.Unlock()
+		for i := 0; i < u.Enc.Count(); i++ {
+			// overflow checked by addrSeqStart
+			obj, _ := u.System.ObjAddrN(first.N() + uint(i))
+			offset := len(addr) + i*elemSize
+			c.set(u, obj, u.Info[offset:offset+elemSize])
+		}
+		return nil
+	}
+
+	objSize := len(addr) + elemSize
+	if len(u.Info) != u.Enc.Count()*objSize {
+		return payloadErr(&u, errInfoSize)
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for i := 0; i+objSize <= len(u.Info); i += objSize {
+		c.set(u, Obj(u.Info[i:i+len(addr)]), u.Info[i+len(addr):i+objSize])
+	}
+	return nil
+}
+
+func (c *PointCache[Orig, Com, Obj]) set(u info.DataUnit[Orig, Com, Obj], addr Obj, element []byte) {
+	key := pointKey[Com, Obj]{u.Addr, addr}
+	p, ok := c.points[key]
+	if !ok {
+		p = new(CachedPoint)
+		c.points[key] = p
+	}
+	p.Type = u.Type
+	p.Cause = u.Cause
+	p.Element = append(p.Element[:0], element...)
+	p.Stale = false
+}
+
+// Point returns a copy of the cache entry, if any.
+func (c *PointCache[Orig, Com, Obj]) Point(com Com, addr Obj) (CachedPoint, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	p, ok := c.points[pointKey[Com, Obj]{com, addr}]
+	if !ok {
+		return CachedPoint{}, false
+	}
+	snapshot := *p
+	snapshot.Element = append([]byte(nil), p.Element...)
+	return snapshot, true
+}
+
+// MarkStale flags all entries as stale, and it sets the NotTopical flag in each
+// quality descriptor, without loss of the values. Updates clear the staleness
+// per point.
+func (c *PointCache[Orig, Com, Obj]) MarkStale() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for _, p := range c.points {
+		p.Stale = true
+		if i, ok := qualOffset(p.Type); ok {
+			p.Element[i] |= byte(info.NotTopical)
+		}
+	}
+}
+
+// QualOffset returns the position of the octet with the NT flag in the
+// information elements, or false when type t has no such flag.
+func qualOffset(t info.TypeID) (index int, ok bool) {
+	switch t {
+	case info.M_SP_NA_1, info.M_SP_TA_1, info.M_SP_TB_1,
+		info.M_DP_NA_1, info.M_DP_TA_1, info.M_DP_TB_1,
+		info.M_EP_TA_1, info.M_EP_TD_1:
+		return 0, true // SIQ, DIQ or SEP
+	case info.M_ST_NA_1, info.M_ST_TA_1, info.M_ST_TB_1,
+		info.M_EP_TB_1, info.M_EP_TE_1, info.M_EP_TC_1, info.M_EP_TF_1:
+		return 1, true // QDS or QDP after 1-octet value
+	case info.M_ME_NA_1, info.M_ME_TA_1, info.M_ME_TD_1,
+		info.M_ME_NB_1, info.M_ME_TB_1, info.M_ME_TE_1:
+		return 2, true // QDS after 2-octet value
+	case info.M_BO_NA_1, info.M_BO_TA_1, info.M_BO_TB_1,
+		info.M_ME_NC_1, info.M_ME_TC_1, info.M_ME_TF_1,
+		info.M_PS_NA_1:
+		return 4, true // QDS after 4-octet value
+	}
+	return 0, false
+}
@@ -0,0 +1,88 @@
+package part5
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pascaldekloe/part5/info"
+)
+
+// Values persist across a reconnect, marked stale until refreshed.
+func TestPointCacheStale(t *testing.T) {
+	var sys info.System[info.OrigAddr0, info.ComAddr8, info.ObjAddr16]
+	x := testExchange()
+	r := x.Report()
+	cache := NewPointCache[info.OrigAddr0, info.ComAddr8, info.ObjAddr16]()
+
+	for n := uint(1); n <= 2; n++ {
+		u, err := r.Object(info.M_ME_NC_1, info.Spont, sys.MustObjAddrN(n), []byte{0, 0, 0x80, 0x3f, 0})
+		if err != nil {
+			t.Fatal("M_ME_NC_1 build error:", err)
+		}
+		if err := cache.Update(u); err != nil {
+			t.Fatal("cache update error:", err)
+		}
+	}
+
+	cache.MarkStale() // session Exit
+
+	for n := uint(1); n <= 2; n++ {
+		p, ok := cache.Point(x.ComAddr, sys.MustObjAddrN(n))
+		switch {
+		case !ok:
+			t.Fatalf("address %d lost on MarkStale", n)
+		case !p.Stale:
+			t.Errorf("address %d not stale after MarkStale", n)
+		case string(p.Element) != "\x00\x00\x80\x3f\x40":
+			t.Errorf("address %d got element %#x, want the value with NT flag", n, p.Element)
+		}
+	}
+
+	// interrogation after reconnect
+	u, err := r.Object(info.M_ME_NC_1, info.Inrogen, sys.MustObjAddrN(1), []byte{0, 0, 0, 0x40, 0})
+	if err != nil {
+		t.Fatal("M_ME_NC_1 build error:", err)
+	}
+	if err := cache.Update(u); err != nil {
+		t.Fatal("cache update error:", err)
+	}
+	if p, _ := cache.Point(x.ComAddr, sys.MustObjAddrN(1)); p.Stale || p.Cause != info.Inrogen || string(p.Element) != "\x00\x00\x00\x40\x00" {
+		t.Errorf("refreshed address 1 got %+v", p)
+	}
+	if p, _ := cache.Point(x.ComAddr, sys.MustObjAddrN(2)); !p.Stale {
+		t.Error("address 2 not stale without refresh")
+	}
+}
+
+// Address sequences must not wrap around.
+func TestPointCacheSeq(t *testing.T) {
+	var sys info.System[info.OrigAddr0, info.ComAddr8, info.ObjAddr16]
+	x := testExchange()
+	cache := NewPointCache[info.OrigAddr0, info.ComAddr8, info.ObjAddr16]()
+
+	overflow := x.NewDataUnit(info.M_ME_NC_1, 0x82, info.Spont)
+	overflow.Info = append(overflow.Info, 0xff, 0xff, 0, 0, 0x80, 0x3f, 0, 0, 0, 0x80, 0x3f, 0)
+	if err := cache.Update(overflow); !errors.Is(err, info.ErrAddrSeq) {
+		t.Errorf("sequence beyond address 65535 got error %v, want %v", err, info.ErrAddrSeq)
+	}
+	if _, ok := cache.Point(x.ComAddr, sys.MustObjAddrN(0)); ok {
+		t.Error("sequence beyond address 65535 cached address 0")
+	}
+
+	timed := x.NewDataUnit(info.M_SP_TA_1, 0x81, info.Spont)
+	timed.Info = append(timed.Info, 1, 0, 1, 0, 0, 0)
+	if err := cache.Update(timed); !errors.Is(err, info.ErrAddrSeqType) {
+		t.Errorf("sequence of M_SP_TA_1 got error %v, want %v", err, info.ErrAddrSeqType)
+	}
+
+	seq, err := x.Report().Seq(info.M_SP_NA_1, info.Inrogen, sys.MustObjAddrN(0xfffe), []byte{1}, []byte{0})
+	if err != nil {
+		t.Fatal("M_SP_NA_1 build error:", err)
+	}
+	if err := cache.Update(seq); err != nil {
+		t.Fatal("cache update error:", err)
+	}
+	if p, ok := cache.Point(x.ComAddr, sys.MustObjAddrN(0xffff)); !ok || string(p.Element) != "\x00" {
+		t.Errorf("address 65535 got %+v, want element 0x00", p)
+	}
+}
@@ -75,11 +75,10 @@ func main() {
 	inroCmd, doInro := stream.mustInroAct()
 
 	addr := net.JoinHostPort(*hostFlag, strconv.FormatUint(uint64(*portFlag), 10))
-	conn, err := net.Dial("tcp", addr)
+	client, err := session.Dial(config, addr)
 	if err != nil {
 		log.Fatal(err)
 	}
-	client := session.TCP(config, conn)
 
 	go stream.streamInbound(client)
 	go stream.streamOutbound(client)
@@ -0,0 +1,246 @@
+package part5
+
+import (
+	"errors"
+	"time"
+
+	"github.com/pascaldekloe/part5/info"
+)
+
+// A Controller consumes information in control direction, i.e., the type codes
+// with a "C_" prefix. Subinterfaces of Controller organise per information
+// type, conform the Monitor setup.
+//
+// Commands with a command qualifier come with the S/E flag, as in q.Select(),
+// set for selection, and clear for execution. See MatchSelectExecute, and see
+// chapter 6.8: “Command transmission” of section 5.
+type Controller[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr] interface {
+	SingleCmdController[Orig, Com, Obj]
+	DoubleCmdController[Orig, Com, Obj]
+	RegulCmdController[Orig, Com, Obj]
+	DelayAcqController[Orig, Com, Obj]
+	ClockSyncController[Orig, Com, Obj]
+}
+
+// SingleCmdController consumes single commands, with the S/E flag conform
+// Controller.
+type SingleCmdController[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr] interface {
+	// SingleCmd gets called for type identifier 45: C_SC_NA_1.
+	SingleCmd(info.DataUnit[Orig, Com, Obj], Obj, info.SinglePt, info.CmdQual)
+}
+
+// DoubleCmdController consumes double commands, with the S/E flag conform
+// Controller.
+type DoubleCmdController[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr] interface {
+	// DoubleCmd gets called for type identifier 46: C_DC_NA_1.
+	DoubleCmd(info.DataUnit[Orig, Com, Obj], Obj, info.DoublePt, info.CmdQual)
+}
+
+// RegulCmdController consumes regulating-step commands, with the S/E flag
+// conform Controller.
+type RegulCmdController[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr] interface {
+	// RegulCmd gets called for type identifier 47: C_RC_NA_1.
+	RegulCmd(info.DataUnit[Orig, Com, Obj], Obj, info.Regul, info.CmdQual)
+}
+
+// DelayAcqController consumes delay acquisition.
+type DelayAcqController[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr] interface {
+	// DelayAcq gets called for type identifier 106: C_CD_NA_1. The delay
+	// is either the transmission delay as specified by the controlling
+	// station (with cause info.Act), or a delay acquisition request (with
+	// cause info.Spont). See chapter 7.3.4.7 of companion standard 101.
+	DelayAcq(info.DataUnit[Orig, Com, Obj], info.CP16Time2a)
+}
+
+// ClockSyncController consumes clock synchronization.
+type ClockSyncController[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr] interface {
+	// ClockSync gets called for type identifier 103: C_CS_NA_1. See
+	// chapter 7.3.4.4 of companion standard 101.
+	ClockSync(info.DataUnit[Orig, Com, Obj], info.CP56Time2a)
+}
+
+type clockSyncProxy[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr] struct {
+	listener func(info.DataUnit[Orig, Com, Obj], time.Time, bool)
+	timeZone *time.Location
+}
+
+// ClockSyncProxy abstracts the ClockSyncController interface into one function.
+// The time tag is interpretated within the time-zone argument. The boolean is
+// true when the summer-time flag [SU] of the tag does not match the daylight
+// saving time of the time-zone at that moment. Controlling stations are known
+// to send wrong SU flags, so such discrepancy should be reported, or rejected
+// even. Time is zero for Invalid() info.CP56Time2a.
+func ClockSyncProxy[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr](listener func(u info.DataUnit[Orig, Com, Obj], t time.Time, suMismatch bool), zone *time.Location) ClockSyncController[Orig, Com, Obj] {
+	return clockSyncProxy[Orig, Com, Obj]{listener, zone}
+}
+
+func (proxy clockSyncProxy[Orig, Com, Obj]) ClockSync(u info.DataUnit[Orig, Com, Obj], tag info.CP56Time2a) {
+	t := tag.Within20thCentury(proxy.timeZone)
+	proxy.listener(u, t, !t.IsZero() && t.IsDST() != tag.SummerTime())
+}
+
+// ErrNotControl rejects an info.DataUnit based on its type identifier.
+var ErrNotControl = errors.New("part5: ASDU type identifier not supported in control direction")
+
+// ControlDataUnit propagates information objects in u to the corresponding
+// listener method from ctl, filtering with ErrNotControl.
+func ControlDataUnit[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr](ctl Controller[Orig, Com, Obj], u info.DataUnit[Orig, Com, Obj]) error {
+	// NOTE: Go can't get the array length from a generic as a constant yet.
+	var addr Obj
+
+	switch u.Type {
+	case info.C_SC_NA_1: // single command
+		if err := singleObj(&u, 1); err != nil {
+			return err
+		}
+		if err := VerifyQual(u); err != nil {
+			return err
+		}
+		b := u.Info[len(addr)]
+		ctl.SingleCmd(u, Obj(u.Info[:len(addr)]), info.SinglePt(b&1), info.CmdQual(b&^1))
+
+	case info.C_DC_NA_1: // double command
+		if err := singleObj(&u, 1); err != nil {
+			return err
+		}
+		if err := VerifyQual(u); err != nil {
+			return err
+		}
+		b := u.Info[len(addr)]
+		ctl.DoubleCmd(u, Obj(u.Info[:len(addr)]), info.DoublePt(b&3), info.CmdQual(b&^3))
+
+	case info.C_RC_NA_1: // regulating-step command
+		if err := singleObj(&u, 1); err != nil {
+			return err
+		}
+		if err := VerifyQual(u); err != nil {
+			return err
+		}
+		b := u.Info[len(addr)]
+		ctl.RegulCmd(u, Obj(u.Info[:len(addr)]), info.Regul(b&3), info.CmdQual(b&^3))
+
+	case info.C_CD_NA_1: // delay acquisition
+		if err := singleObj(&u, 2); err != nil {
+			return err
+		}
+		ctl.DelayAcq(u, info.CP16Time2a(u.Info[len(addr):len(addr)+2]))
+
+	case info.C_CS_NA_1: // clock synchronization
+		if err := singleObj(&u, 7); err != nil {
+			return err
+		}
+		ctl.ClockSync(u, info.CP56Time2a(u.Info[len(addr):len(addr)+7]))
+
+	default:
+		return ErrNotControl
+	}
+	return nil
+}
+
+var errSingleObj = errors.New("part5: variable structure qualifier of command not 1")
+
+// SingleObj verifies the payload to be one information object with an address
+// and encSize octets of information element(s).
+func singleObj[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr](u *info.DataUnit[Orig, Com, Obj], encSize int) error {
+	var addr Obj
+	if u.Enc != 1 {
+		return errSingleObj
+	}
+	if len(u.Info) != len(addr)+encSize {
+		return payloadErr(u, errInfoSize)
+	}
+	return nil
+}
+
+// Select and execute may mismatch on the command.
+var (
+	ErrSelectMismatch = errors.New("part5: execute command does not match the selection")
+	errNotSelect      = errors.New("part5: select command without S/E flag")
+	errNotExecute     = errors.New("part5: execute command with S/E flag")
+	errNoSelect       = errors.New("part5: ASDU type identifier does not select")
+)
+
+// ErrCmdQual rejects a qualifier of command, or a qualifier of set-point
+// command, with a value which is reserved for standard definitions.
+var ErrCmdQual = errors.New("part5: qualifier of command reserved for the type")
+
+// VerifyQual verifies the qualifier of a command, if any, to be applicable to
+// the type. The additional definition of info.CmdQual (for single, double and
+// regulating-step commands) permits 0..3 and 9..31 only. The QL value of
+// info.SetPtQual (for set-point commands) permits 0 and 64..127 only. Thus, a
+// pulse-duration qualifier on a set-point command gets ErrCmdQual. Types
+// without a qualifier of command pass.
+func VerifyQual[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr](u info.DataUnit[Orig, Com, Obj]) error {
+	var addr Obj
+	qi, ok := qualIndex(u.Type)
+	if !ok {
+		return nil
+	}
+	qi += len(addr)
+	if u.Enc != 1 || len(u.Info) <= qi {
+		return errSingleObj
+	}
+	b := u.Info[qi]
+
+	if qi == len(addr) {
+		// chapter 7.2.6.26 of companion standard 101
+		if q := info.CmdQual(b &^ 3); q.Additional() >= 4 && q.Additional() <= 8 {
+			return ErrCmdQual
+		}
+	} else {
+		// chapter 7.2.6.39 of companion standard 101
+		if q := info.SetPtQual(b); q.N() >= 1 && q.N() <= 63 {
+			return ErrCmdQual
+		}
+	}
+	return nil
+}
+
+// QualIndex returns the position of the qualifier with the S/E flag in the
+// information elements, or false when type t can not select.
+func qualIndex(t info.TypeID) (index int, ok bool) {
+	switch t {
+	case info.C_SC_NA_1, info.C_DC_NA_1, info.C_RC_NA_1,
+		info.C_SC_TA_1, info.C_DC_TA_1, info.C_RC_TA_1:
+		return 0, true // SCO, DCO or RCO
+	case info.C_SE_NA_1, info.C_SE_NB_1,
+		info.C_SE_TA_1, info.C_SE_TB_1:
+		return 2, true // QOS after 2-octet value
+	case info.C_SE_NC_1, info.C_SE_TC_1:
+		return 4, true // QOS after 4-octet value
+	}
+	return 0, false
+}
+
+// MatchSelectExecute verifies exec(ution) to match a prior sel(ection), i.e.,
+// the same command with the S/E flag cleared. Time tags, if any, are excluded
+// from the comparison. See chapter 6.8: “Command transmission” of section 5.
+func MatchSelectExecute[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr](sel, exec info.DataUnit[Orig, Com, Obj]) error {
+	var addr Obj
+	qi, ok := qualIndex(sel.Type)
+	if !ok {
+		return errNoSelect
+	}
+	qi += len(addr)
+	if sel.Enc != 1 || len(sel.Info) <= qi {
+		return errSingleObj
+	}
+
+	switch {
+	case sel.Info[qi]&0x80 == 0:
+		return errNotSelect
+	case exec.Type != sel.Type,
+		exec.Enc != sel.Enc,
+		exec.Cause != sel.Cause,
+		exec.Orig != sel.Orig,
+		exec.Addr != sel.Addr,
+		len(exec.Info) != len(sel.Info),
+		string(exec.Info[:qi]) != string(sel.Info[:qi]):
+		return ErrSelectMismatch
+	case exec.Info[qi]&0x80 != 0:
+		return errNotExecute
+	case exec.Info[qi] != sel.Info[qi]&^0x80:
+		return ErrSelectMismatch
+	}
+	return nil
+}
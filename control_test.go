@@ -0,0 +1,401 @@
+package part5
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/pascaldekloe/part5/info"
+)
+
+type delayAcqFunc[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr] func(info.DataUnit[Orig, Com, Obj], info.CP16Time2a)
+
+func (f delayAcqFunc[Orig, Com, Obj]) DelayAcq(u info.DataUnit[Orig, Com, Obj], delay info.CP16Time2a) {
+	f(u, delay)
+}
+
+type doubleCmdFunc[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr] func(info.DataUnit[Orig, Com, Obj], Obj, info.DoublePt, info.CmdQual)
+
+func (f doubleCmdFunc[Orig, Com, Obj]) DoubleCmd(u info.DataUnit[Orig, Com, Obj], addr Obj, pt info.DoublePt, q info.CmdQual) {
+	f(u, addr, pt, q)
+}
+
+func TestDoubleCmdSelect(t *testing.T) {
+	var sys info.System[info.OrigAddr8, info.ComAddr16, info.ObjAddr24]
+	x := Exchange[info.OrigAddr8, info.ComAddr16, info.ObjAddr24]{
+		OrigAddr: sys.MustOrigAddrN(9),
+		ComAddr:  sys.MustComAddrN(1001),
+	}
+
+	var got []bool
+	ctl := NewControlDelegate(sys)
+	ctl.DoubleCmdController = doubleCmdFunc[info.OrigAddr8, info.ComAddr16, info.ObjAddr24](
+		func(_ info.DataUnit[info.OrigAddr8, info.ComAddr16, info.ObjAddr24], addr info.ObjAddr24, pt info.DoublePt, q info.CmdQual) {
+			if addr.N() != 42 || pt != info.DeterminatedOn || q.Additional() != 1 {
+				t.Errorf("got address %d, point %s and additional %d; want 42, %s and 1", addr.N(), pt, q.Additional(), info.DoublePt(info.DeterminatedOn))
+			}
+			got = append(got, q.Select())
+		})
+
+	var q info.CmdQual
+	q.SetAdditional(1)
+	exec := x.Command().DoubleCmd(sys.MustObjAddrN(42), info.DeterminatedOn, q)
+	q.FlagSelect()
+	sel := x.Command().DoubleCmd(sys.MustObjAddrN(42), info.DeterminatedOn, q)
+
+	for _, u := range []info.DataUnit[info.OrigAddr8, info.ComAddr16, info.ObjAddr24]{sel, exec} {
+		if err := ControlDataUnit(ctl, u); err != nil {
+			t.Fatal("control error:", err)
+		}
+	}
+	if len(got) != 2 || !got[0] || got[1] {
+		t.Errorf("got select flags %t, want [true false]", got)
+	}
+}
+
+func TestDelayAcq(t *testing.T) {
+	var sys info.System[info.OrigAddr8, info.ComAddr16, info.ObjAddr24]
+	x := Exchange[info.OrigAddr8, info.ComAddr16, info.ObjAddr24]{
+		OrigAddr: sys.MustOrigAddrN(9),
+		ComAddr:  sys.MustComAddrN(1001),
+	}
+
+	var delay info.CP16Time2a
+	delay.SetMillis(1234)
+
+	// wire round trip
+	u := sys.NewDataUnit()
+	if err := u.Adopt(x.Command().DelayAcq(delay).Append(nil)); err != nil {
+		t.Fatal("parse error:", err)
+	}
+
+	var calls int
+	ctl := NewControlDelegate(sys)
+	ctl.DelayAcqController = delayAcqFunc[info.OrigAddr8, info.ComAddr16, info.ObjAddr24](
+		func(u info.DataUnit[info.OrigAddr8, info.ComAddr16, info.ObjAddr24], got info.CP16Time2a) {
+			calls++
+			if u.Cause != info.Act {
+				t.Errorf("got cause %s, want %s", u.Cause, info.Act)
+			}
+			if got.Millis() != 1234 {
+				t.Errorf("got delay of %d ms, want 1234 ms", got.Millis())
+			}
+		})
+
+	if err := ControlDataUnit(ctl, u); err != nil {
+		t.Fatal("control error:", err)
+	}
+	if calls != 1 {
+		t.Errorf("got %d delay acquisition calls, want 1", calls)
+	}
+
+	u.Info = u.Info[:len(u.Info)-1]
+	if err := ControlDataUnit(ctl, u); !errors.Is(err, errInfoSize) {
+		t.Errorf("truncated payload got error %v, want %v", err, errInfoSize)
+	} else if e := (info.DecodeError{}); !errors.As(err, &e) || e.Offset != 6 {
+		t.Errorf("truncated payload got error %#v, want info.DecodeError at offset 6", err)
+	}
+}
+
+// Controlling stations may send a wrong summer-time flag.
+func TestClockSyncSU(t *testing.T) {
+	zone, err := time.LoadLocation("Europe/Amsterdam")
+	if err != nil {
+		t.Skip("time-zone unavailable:", err)
+	}
+	var sys info.System[info.OrigAddr8, info.ComAddr16, info.ObjAddr24]
+	cmd := Exchange[info.OrigAddr8, info.ComAddr16, info.ObjAddr24]{
+		OrigAddr: sys.MustOrigAddrN(9),
+		ComAddr:  sys.MustComAddrN(1001),
+	}.Command()
+
+	var got []bool
+	ctl := NewControlDelegate(sys)
+	ctl.ClockSyncController = ClockSyncProxy(func(_ info.DataUnit[info.OrigAddr8, info.ComAddr16, info.ObjAddr24], _ time.Time, suMismatch bool) {
+		got = append(got, suMismatch)
+	}, zone)
+
+	var tag info.CP56Time2a
+	tag.Set(time.Date(2024, 7, 1, 12, 0, 0, 0, zone))
+	if !tag.SummerTime() {
+		t.Fatal("summer-time flag not set in July")
+	}
+	consistent := cmd.ClockSync(tag)
+	tag[3] &^= 0x80 // clear SU
+	inconsistent := cmd.ClockSync(tag)
+
+	for _, u := range []info.DataUnit[info.OrigAddr8, info.ComAddr16, info.ObjAddr24]{consistent, inconsistent} {
+		if err := ControlDataUnit(ctl, u); err != nil {
+			t.Fatal("control error:", err)
+		}
+	}
+	if len(got) != 2 || got[0] || !got[1] {
+		t.Errorf("got SU mismatches %t, want [false true]", got)
+	}
+}
+
+func TestConfirm(t *testing.T) {
+	var sys info.System[info.OrigAddr8, info.ComAddr16, info.ObjAddr24]
+	x := Exchange[info.OrigAddr8, info.ComAddr16, info.ObjAddr24]{
+		OrigAddr: sys.MustOrigAddrN(9),
+		ComAddr:  sys.MustComAddrN(1001),
+	}
+	req := x.Command().SingleCmd(sys.MustObjAddrN(42), info.On, info.CmdQual(0))
+	req.Cause |= info.TestFlag
+
+	if got := ConfirmPositive(req); ConOf(got, req) != nil {
+		t.Errorf("positive confirmation %s got ConOf error %v", got, ConOf(got, req))
+	}
+	if got := ConfirmNegative(req); got.Cause != info.Actcon|info.NegFlag|info.TestFlag {
+		t.Errorf("negative confirmation got cause %s, want %s", got.Cause, info.Actcon|info.NegFlag|info.TestFlag)
+	} else if err := ConOf(got, req); err != ErrConNeg {
+		t.Errorf("negative confirmation got ConOf error %v, want %v", err, ErrConNeg)
+	}
+	if got := Reject(req, info.UnkInfo); got.Cause != info.UnkInfo|info.NegFlag|info.TestFlag {
+		t.Errorf("rejection got cause %s, want %s", got.Cause, info.UnkInfo|info.NegFlag|info.TestFlag)
+	}
+
+	req.Cause = info.Deact
+	if got := ConfirmNegative(req); got.Cause != info.Deactcon|info.NegFlag {
+		t.Errorf("negative deactivation confirmation got cause %s, want %s", got.Cause, info.Deactcon|info.NegFlag)
+	}
+}
+
+func TestConfirmOnly(t *testing.T) {
+	var sys info.System[info.OrigAddr8, info.ComAddr16, info.ObjAddr24]
+	x := Exchange[info.OrigAddr8, info.ComAddr16, info.ObjAddr24]{
+		OrigAddr: sys.MustOrigAddrN(9),
+		ComAddr:  sys.MustComAddrN(1001),
+	}
+	req := x.Command().SingleCmd(sys.MustObjAddrN(42), info.On, info.CmdQual(0))
+
+	got, err := x.Report().ConfirmOnly(req, info.M_SP_NA_1, []byte{byte(info.NewSinglePtQual(info.Off, 0))})
+	if err != nil {
+		t.Fatal("confirm only error:", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("got %d responses, want 3", len(got))
+	}
+	if err := ConOf(got[0], req); err != nil {
+		t.Errorf("first response %s got ConOf error %v", got[0], err)
+	}
+	if got[1].Type != info.M_SP_NA_1 || got[1].Cause != info.Retrem {
+		t.Errorf("second response got %s %s, want M_SP_NA_1 retrem", got[1].Type, got[1].Cause)
+	}
+	if string(got[1].Info[:3]) != string(req.Info[:3]) {
+		t.Errorf("status report got address %#x, want %#x", got[1].Info[:3], req.Info[:3])
+	}
+	if err := ConOf(got[2], req); err != ErrTerm {
+		t.Errorf("third response %s got ConOf error %v, want %v", got[2], err, ErrTerm)
+	}
+
+	// no termination on deactivation
+	req.Cause = info.Deact
+	got, err = x.Report().ConfirmOnly(req, info.M_SP_NA_1, []byte{byte(info.NewSinglePtQual(info.Off, 0))})
+	if err != nil {
+		t.Fatal("confirm only error:", err)
+	}
+	if len(got) != 2 || got[0].Cause != info.Deactcon {
+		t.Errorf("deactivation got responses %s, want deactcon and retrem", got)
+	}
+}
+
+// A breaker reports its state after operation.
+func TestRetrem(t *testing.T) {
+	var sys info.System[info.OrigAddr8, info.ComAddr16, info.ObjAddr24]
+	x := Exchange[info.OrigAddr8, info.ComAddr16, info.ObjAddr24]{
+		OrigAddr: sys.MustOrigAddrN(9),
+		ComAddr:  sys.MustComAddrN(1001),
+	}
+	req := x.Command().DoubleCmd(sys.MustObjAddrN(42), info.DoublePt(info.DeterminatedOn), 0)
+
+	var tag info.CP56Time2a
+	tag.Set(time.Date(2024, 2, 29, 13, 14, 15, 0, time.UTC))
+	state := info.DoublePtQual(info.DeterminatedOn)
+	u, err := x.Report().Retrem(req, info.M_DP_TB_1, append([]byte{byte(state)}, tag[:]...))
+	if err != nil {
+		t.Fatal("Retrem error:", err)
+	}
+
+	var got []string
+	mon := NewMonitorDelegate(sys)
+	mon.DoublePtMonitor = DoublePtProxy(func(u info.DataUnit[info.OrigAddr8, info.ComAddr16, info.ObjAddr24], addr info.ObjAddr24, p info.DoublePtQual, _ time.Time) {
+		got = append(got, fmt.Sprintf("%s %s %d %s", u.Type, u.Cause, addr.N(), p))
+	}, time.UTC, 0)
+	if err := MonitorDataUnit(mon, u); err != nil {
+		t.Fatal("monitor error:", err)
+	}
+	want := fmt.Sprintf("M_DP_TB_1 retrem 42 %s", state)
+	if len(got) != 1 || got[0] != want {
+		t.Errorf("got %q, want [%q]", got, want)
+	}
+
+	u, err = x.Report().Retloc(info.M_DP_NA_1, sys.MustObjAddrN(42), []byte{byte(state)})
+	if err != nil {
+		t.Fatal("Retloc error:", err)
+	}
+	if u.Cause != info.Retloc {
+		t.Errorf("got cause %s, want %s", u.Cause, info.Retloc)
+	}
+}
+
+func TestMatchSelectExecute(t *testing.T) {
+	var sys info.System[info.OrigAddr8, info.ComAddr16, info.ObjAddr24]
+	cmd := Exchange[info.OrigAddr8, info.ComAddr16, info.ObjAddr24]{
+		OrigAddr: sys.MustOrigAddrN(9),
+		ComAddr:  sys.MustComAddrN(1001),
+	}.Command()
+
+	var q info.SetPtQual
+	q.FlagSelect()
+	sel := cmd.FloatSetPt(sys.MustObjAddrN(42), 0.5, q)
+
+	if err := MatchSelectExecute(sel, cmd.FloatSetPt(sys.MustObjAddrN(42), 0.5, 0)); err != nil {
+		t.Error("matching execute got error:", err)
+	}
+	if err := MatchSelectExecute(sel, cmd.FloatSetPt(sys.MustObjAddrN(42), 0.75, 0)); err != ErrSelectMismatch {
+		t.Errorf("other value got error %v, want %v", err, ErrSelectMismatch)
+	}
+	if err := MatchSelectExecute(sel, cmd.FloatSetPt(sys.MustObjAddrN(43), 0.5, 0)); err != ErrSelectMismatch {
+		t.Errorf("other address got error %v, want %v", err, ErrSelectMismatch)
+	}
+	if err := MatchSelectExecute(sel, sel); err != errNotExecute {
+		t.Errorf("select as execute got error %v, want %v", err, errNotExecute)
+	}
+}
+
+func TestVerifyQual(t *testing.T) {
+	var sys info.System[info.OrigAddr0, info.ComAddr8, info.ObjAddr16]
+	cmd := testExchange().Command()
+	addr := sys.MustObjAddrN(42)
+
+	var pulse info.CmdQual
+	pulse.SetAdditional(1) // short pulse duration
+	var reserved info.CmdQual
+	reserved.SetAdditional(5)
+	var private info.SetPtQual
+	private.SetN(64)
+
+	golden := []struct {
+		u    info.DataUnit[info.OrigAddr0, info.ComAddr8, info.ObjAddr16]
+		want error
+	}{
+		{cmd.SingleCmd(addr, info.On, pulse), nil},
+		{cmd.DoubleCmd(addr, info.DeterminatedOn, pulse), nil},
+		{cmd.RegulCmd(addr, info.Higher, reserved), ErrCmdQual},
+		{cmd.FloatSetPt(addr, 1.5, 0), nil},
+		{cmd.FloatSetPt(addr, 1.5, private), nil},
+		// CmdQual misapplied as SetPtQual
+		{cmd.FloatSetPt(addr, 1.5, info.SetPtQual(pulse)), ErrCmdQual},
+		{cmd.ScaledSetPt(addr, 7, info.SetPtQual(pulse)), ErrCmdQual},
+		{cmd.Inro(), nil},
+	}
+	for _, gold := range golden {
+		if err := VerifyQual(gold.u); err != gold.want {
+			t.Errorf("%s got error %v, want %v", gold.u, err, gold.want)
+		}
+	}
+
+	// parser rejects before the Controller
+	ctl := NewControlDelegate(sys)
+	ctl.SingleCmdController = singleCmdFunc[info.OrigAddr0, info.ComAddr8, info.ObjAddr16](
+		func(u info.DataUnit[info.OrigAddr0, info.ComAddr8, info.ObjAddr16], _ info.ObjAddr16, _ info.SinglePt, _ info.CmdQual) {
+			t.Errorf("Controller got %s", u)
+		})
+	if err := ControlDataUnit(ctl, cmd.SingleCmd(addr, info.On, reserved)); err != ErrCmdQual {
+		t.Errorf("parser got error %v, want %v", err, ErrCmdQual)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	var sys info.System[info.OrigAddr8, info.ComAddr8, info.ObjAddr16]
+	x := Exchange[info.OrigAddr8, info.ComAddr8, info.ObjAddr16]{
+		OrigAddr: sys.MustOrigAddrN(3),
+		ComAddr:  sys.MustComAddrN(7),
+	}
+	global := x
+	global.ComAddr = sys.MustComAddrN(255)
+	other := x
+	other.ComAddr = sys.MustComAddrN(8)
+	operator := x
+	operator.OrigAddr = sys.MustOrigAddrN(4)
+	addr := sys.MustObjAddrN(42)
+
+	truncated := x.Command().SingleCmd(addr, info.On, 0)
+	truncated.Info = truncated.Info[:len(truncated.Info)-1]
+	extra := x.Command().SingleCmd(addr, info.On, 0)
+	extra.Info = append(extra.Info, 0)
+	spont := x.Command().SingleCmd(addr, info.On, 0)
+	spont.Cause = info.Spont
+	cmdSeq := x.Command().SingleCmd(addr, info.On, 0)
+	cmdSeq.Enc |= 0x80
+	reserved := x.Command().SingleCmd(addr, info.On, 0)
+	reserved.Type = 52
+	typeZero := x.Command().SingleCmd(addr, info.On, 0)
+	typeZero.Type = 0
+	causeZero := x.Command().SingleCmd(addr, info.On, 0)
+	causeZero.Cause = info.TestFlag
+	comAddrZero := x.Command().SingleCmd(addr, info.On, 0)
+	comAddrZero.Addr = sys.MustComAddrN(0)
+	seq, err := x.Report().Seq(info.M_SP_NA_1, info.Inrogen, addr, []byte{0}, []byte{1})
+	if err != nil {
+		t.Fatal("M_SP_NA_1 build error:", err)
+	}
+	seqOverflow := seq
+	seqOverflow.Info = append([]byte{0xff, 0xff}, seq.Info[2:]...)
+
+	golden := []struct {
+		u    info.DataUnit[info.OrigAddr8, info.ComAddr8, info.ObjAddr16]
+		want error
+	}{
+		{x.Command().SingleCmd(addr, info.On, 0), nil},
+		{ConfirmNegative(x.Command().SingleCmd(addr, info.On, 0)), nil},
+		{x.Command().Read(addr), nil},
+		{global.Command().Inro(), nil},
+		{x.Report().InitEnd(0), nil},
+		{seq, nil},
+		{global.Command().SingleCmd(addr, info.On, 0), errGlobalAddr},
+		{other.Command().SingleCmd(addr, info.On, 0), errComAddr},
+		{operator.Command().SingleCmd(addr, info.On, 0), errOrigAddr},
+		{truncated, errInfoSize},
+		{extra, errInfoSize},
+		{spont, errCauseType},
+		{cmdSeq, info.ErrAddrSeqType},
+		{seqOverflow, info.ErrAddrSeq},
+		{reserved, errTypeReserve},
+		{typeZero, info.ErrTypeZero},
+		{causeZero, info.ErrCauseZero},
+		{comAddrZero, info.ErrComAddrZero},
+	}
+	for _, gold := range golden {
+		if err := x.Validate(gold.u); !errors.Is(err, gold.want) {
+			t.Errorf("%s got error %v, want %v", gold.u, err, gold.want)
+		}
+	}
+
+	// responses mirror the originator of requests
+	outstation := x
+	outstation.OrigAddr = sys.MustOrigAddrN(0)
+	if err := outstation.Validate(operator.Command().SingleCmd(addr, info.On, 0)); err != nil {
+		t.Error("exchange without originator got error:", err)
+	}
+
+	// direction
+	req := x.Command().SingleCmd(addr, info.On, 0)
+	if err := x.Command().Validate(req); err != nil {
+		t.Error("command request got error:", err)
+	}
+	if err := x.Report().Validate(req); err != errCauseDir {
+		t.Errorf("request in monitor direction got error %v, want %v", err, errCauseDir)
+	}
+	if err := x.Report().Validate(ConfirmPositive(req)); err != nil {
+		t.Error("command confirmation got error:", err)
+	}
+	if err := x.Command().Validate(ConfirmPositive(req)); err != errCauseDir {
+		t.Errorf("confirmation in control direction got error %v, want %v", err, errCauseDir)
+	}
+	if err := x.Command().Validate(seq); err != errCauseDir {
+		t.Errorf("monitor information in control direction got error %v, want %v", err, errCauseDir)
+	}
+}
@@ -0,0 +1,353 @@
+package part5
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/pascaldekloe/part5/info"
+	"github.com/pascaldekloe/part5/session"
+)
+
+// ControlledStation serves commands over a session, i.e., the outstation or
+// the slave. Interrogation is served with Inro, and read commands are served
+// with Read. All other commands go to a Controller, one at a time, unless
+// CmdMax is set.
+type ControlledStation[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr] struct {
+	transport *session.Transport
+	ctl       Controller[Orig, Com, Obj]
+
+	// Inro produces the information objects on interrogation. The cause
+	// is info.Inrogen for station interrogation, or info.Inro1 up to and
+	// including info.Inro16 for group interrogation, plus the TestFlag of
+	// the request. Each information object goes to send. Inro must return
+	// once send fails, which happens on deactivation. Interrogation gets a
+	// negative confirmation when nil.
+	Inro func(c info.Cause, send func(info.DataUnit[Orig, Com, Obj]) error) error
+
+	// Read produces the current value of an information object on a read
+	// command, i.e., C_RD_NA_1. The cause is info.Req plus the TestFlag of
+	// the request. Addresses without information should return false, which
+	// gets the request rejected with info.UnkInfo. Read commands are
+	// rejected with info.UnkType when nil. Each read command gets its own
+	// routine, so Read must be safe for concurrent use.
+	Read func(addr Obj, c info.Cause) (info.DataUnit[Orig, Com, Obj], bool)
+
+	// InitAddrs gets an end of initialization [M_EI_NA_1] per common
+	// address, on class 1 with the first Up passed to SetLevel. Controlling
+	// stations may not interrogate after a restart without it. See chapter
+	// 7.4.1 of companion standard 101.
+	InitAddrs []Com
+	// InitCause goes with each end of initialization, e.g., local power on
+	// with NoChangeInitCause(0).
+	InitCause info.InitCause
+
+	// Unhandled gets called for each inbound ASDU which is not accepted,
+	// when not nil. Errors from Inro, and submission errors of read
+	// responses, are reported with the request. Unhandled must be safe for
+	// concurrent use.
+	Unhandled func(info.DataUnit[Orig, Com, Obj], error)
+
+	// CmdMax enables concurrent execution of commands when non-zero. Each
+	// command gets its own routine, with CmdMax as an upper limit. Commands
+	// beyond the limit get a negative confirmation, without invocation of
+	// the Controller.
+	CmdMax int
+
+	cmdSlots    chan struct{}  // semaphore for CmdMax, owned by Run
+	cmdRoutines sync.WaitGroup // commands and responses in progress
+
+	initMutex sync.Mutex
+	initSent  int // number of InitAddrs submitted
+
+	// interrogation in progress, if any, owned by Run
+	inroCancel context.CancelFunc
+	inroDone   chan struct{}
+}
+
+// NewControlledStation returns a new station which operates on transport t.
+// Run must be called to receive.
+func NewControlledStation[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr](t *session.Transport, ctl Controller[Orig, Com, Obj]) *ControlledStation[Orig, Com, Obj] {
+	return &ControlledStation[Orig, Com, Obj]{
+		transport: t,
+		ctl:       ctl,
+	}
+}
+
+// Run reads the inbound of the transport until closed. Any interrogation in
+// progress is stopped, and any command in progress is awaited before return.
+func (s *ControlledStation[Orig, Com, Obj]) Run() {
+	defer s.cmdRoutines.Wait()
+	defer s.stopInro()
+	if s.CmdMax > 0 {
+		s.cmdSlots = make(chan struct{}, s.CmdMax)
+	}
+
+	var sys info.System[Orig, Com, Obj]
+	for payload := range s.transport.In {
+		u := sys.NewDataUnit()
+		if err := u.Adopt(payload); err != nil {
+			s.unhandled(u, err)
+			continue
+		}
+
+		switch u.Type {
+		case info.C_IC_NA_1:
+			s.interrogation(u)
+			continue
+		case info.C_RD_NA_1:
+			s.read(u)
+			continue
+		}
+		if s.cmdSlots == nil || !isCommand(u.Type) {
+			if err := ControlDataUnit(s.ctl, u); err != nil {
+				s.unhandled(u, err)
+			}
+			continue
+		}
+		s.control(u)
+	}
+}
+
+// Control passes u to the Controller in a separate routine, when within the
+// CmdMax limit.
+func (s *ControlledStation[Orig, Com, Obj]) control(u info.DataUnit[Orig, Com, Obj]) {
+	select {
+	case s.cmdSlots <- struct{}{}:
+		break
+	default:
+		// busy
+		s.respond(s.transport.Class1, ConfirmNegative(u))
+		return
+	}
+
+	s.cmdRoutines.Add(1)
+	go func() {
+		defer s.cmdRoutines.Done()
+		defer func() { <-s.cmdSlots }()
+
+		if err := ControlDataUnit(s.ctl, u); err != nil {
+			s.unhandled(u, err)
+		}
+	}()
+}
+
+func (s *ControlledStation[Orig, Com, Obj]) unhandled(u info.DataUnit[Orig, Com, Obj], err error) {
+	if s.Unhandled != nil {
+		s.Unhandled(u, err)
+	}
+}
+
+// Submit passes u to class, and it does not wait for the delivery.
+func (s *ControlledStation[Orig, Com, Obj]) submit(ctx context.Context, class chan<- *session.Outbound, u info.DataUnit[Orig, Com, Obj]) error {
+	select {
+	case class <- session.NewOutbound(u.Append(nil)):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// SetLevel notifies the station of a status change from the session, i.e.,
+// Station Level. The first Up sends an end of initialization for each of the
+// InitAddrs. An error leaves the remainder for the next Up. FollowLevel may
+// be used instead, when nothing else reads the Station Level.
+func (s *ControlledStation[Orig, Com, Obj]) SetLevel(ctx context.Context, l session.Level) error {
+	if l != session.Up {
+		return nil
+	}
+	s.initMutex.Lock()
+	defer s.initMutex.Unlock()
+	for ; s.initSent < len(s.InitAddrs); s.initSent++ {
+		x := Exchange[Orig, Com, Obj]{ComAddr: s.InitAddrs[s.initSent]}
+		if err := s.SendClass(ctx, x.Report().InitEnd(s.InitCause), 1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FollowLevel passes each level to SetLevel until levels is closed, e.g., with
+// Station Level, which then is read exclusively by FollowLevel.
+func (s *ControlledStation[Orig, Com, Obj]) FollowLevel(levels <-chan session.Level) {
+	for l := range levels {
+		// no error without cancellation
+		s.SetLevel(context.Background(), l)
+	}
+}
+
+var errClass = errors.New("part5: transmission class neither 1 nor 2")
+
+// Send passes u to the class of its cause of transmission, and it does not
+// wait for the delivery. Cyclic, background and interrogated information goes
+// to class 2, as do the responses to counter requests. All other causes, such
+// as spontaneous events, go to class 1.
+func (s *ControlledStation[Orig, Com, Obj]) Send(ctx context.Context, u info.DataUnit[Orig, Com, Obj]) error {
+	return s.SendClass(ctx, u, classOf(u.Cause))
+}
+
+// SendClass passes u to class 1 or class 2, overriding the default of Send,
+// and it does not wait for the delivery.
+func (s *ControlledStation[Orig, Com, Obj]) SendClass(ctx context.Context, u info.DataUnit[Orig, Com, Obj], class int) error {
+	switch class {
+	case 1:
+		return s.submit(ctx, s.transport.Class1, u)
+	case 2:
+		return s.submit(ctx, s.transport.Class2, u)
+	default:
+		return errClass
+	}
+}
+
+// ClassOf returns the default transmission class for cause c.
+func classOf(c info.Cause) int {
+	c &^= info.TestFlag | info.NegFlag
+	switch {
+	case c == info.Cyclic, c == info.Back,
+		c >= info.Inrogen && c <= info.Reqco4:
+		return 2
+	}
+	return 1
+}
+
+// Read serves C_RD_NA_1 with a response from Read, or with a rejection, in a
+// separate routine, which Run awaits on return.
+func (s *ControlledStation[Orig, Com, Obj]) read(req info.DataUnit[Orig, Com, Obj]) {
+	s.cmdRoutines.Add(1)
+	go func() {
+		defer s.cmdRoutines.Done()
+		if err := s.Send(context.Background(), s.readResponse(req)); err != nil {
+			s.unhandled(req, err)
+		}
+	}()
+}
+
+// ReadResponse returns the answer to a C_RD_NA_1 request.
+func (s *ControlledStation[Orig, Com, Obj]) readResponse(req info.DataUnit[Orig, Com, Obj]) info.DataUnit[Orig, Com, Obj] {
+	var addr Obj
+	switch {
+	case s.Read == nil:
+		return Reject(req, info.UnkType)
+	case req.Cause&^info.TestFlag != info.Req:
+		return Reject(req, info.UnkCause)
+	}
+	if err := singleObj(&req, 0); err != nil {
+		s.unhandled(req, err)
+		return Reject(req, info.UnkInfo)
+	}
+
+	res, ok := s.Read(Obj(req.Info[:len(addr)]), info.Req|req.Cause&info.TestFlag)
+	if !ok {
+		return Reject(req, info.UnkInfo)
+	}
+	return res
+}
+
+var errInroQual = errors.New("part5: qualifier of interrogation not in range 20..36")
+
+// Interrogation serves C_IC_NA_1, conform chapter 7.4.5 of companion standard
+// 101. All responses go to class 2, such that the confirmation and the
+// termination are kept in order with the information objects. Submission is
+// never from the Run routine, as a blocked class 2 would hold back any
+// deactivation.
+func (s *ControlledStation[Orig, Com, Obj]) interrogation(req info.DataUnit[Orig, Com, Obj]) {
+	var addr Obj
+	if err := singleObj(&req, 1); err != nil {
+		s.unhandled(req, err)
+		s.respond(s.transport.Class2, Reject(req, info.UnkInfo))
+		return
+	}
+	if Obj(req.Info[:len(addr)]) != addr {
+		s.unhandled(req, info.ErrObjAddrZero)
+		s.respond(s.transport.Class2, Reject(req, info.UnkInfo))
+		return
+	}
+	qual := req.Info[len(addr)]
+	if qual < 20 || qual > 36 {
+		s.unhandled(req, errInroQual)
+		s.respond(s.transport.Class2, Reject(req, info.UnkInfo))
+		return
+	}
+
+	switch req.Cause &^ info.TestFlag {
+	case info.Act:
+		if s.Inro == nil || s.inroBusy() {
+			s.respond(s.transport.Class2, ConfirmNegative(req))
+			return
+		}
+		s.startInro(req, info.Inrogen+info.Cause(qual-20)|req.Cause&info.TestFlag)
+
+	case info.Deact:
+		if !s.inroBusy() {
+			s.respond(s.transport.Class2, ConfirmNegative(req))
+			return
+		}
+		// no more information objects after the confirmation
+		s.stopInro()
+		s.respond(s.transport.Class2, ConfirmPositive(req))
+
+	default:
+		s.respond(s.transport.Class2, Reject(req, info.UnkCause))
+	}
+}
+
+// Respond passes u to class in a separate routine, which Run awaits on
+// return. A blocked class can not hold back Run that way.
+func (s *ControlledStation[Orig, Com, Obj]) respond(class chan<- *session.Outbound, u info.DataUnit[Orig, Com, Obj]) {
+	s.cmdRoutines.Add(1)
+	go func() {
+		defer s.cmdRoutines.Done()
+		s.submit(context.Background(), class, u)
+	}()
+}
+
+// InroBusy returns whether an interrogation is in progress.
+func (s *ControlledStation[Orig, Com, Obj]) inroBusy() bool {
+	if s.inroDone == nil {
+		return false
+	}
+	select {
+	case <-s.inroDone:
+		return false
+	default:
+		return true
+	}
+}
+
+// StartInro confirms the request, and it runs Inro, in a separate routine. The
+// request is terminated when Inro completes without deactivation.
+func (s *ControlledStation[Orig, Com, Obj]) startInro(req info.DataUnit[Orig, Com, Obj], c info.Cause) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	s.inroCancel = cancel
+	s.inroDone = done
+
+	go func() {
+		defer close(done)
+
+		if s.submit(ctx, s.transport.Class2, ConfirmPositive(req)) != nil {
+			return // deactivated
+		}
+		err := s.Inro(c, func(u info.DataUnit[Orig, Com, Obj]) error {
+			return s.submit(ctx, s.transport.Class2, u)
+		})
+		if ctx.Err() != nil {
+			return // deactivated
+		}
+		if err != nil {
+			s.unhandled(req, err)
+		}
+		s.submit(ctx, s.transport.Class2, Terminate(req))
+	}()
+}
+
+// StopInro cancels any interrogation in progress, and it awaits completion.
+func (s *ControlledStation[Orig, Com, Obj]) stopInro() {
+	if s.inroDone == nil {
+		return
+	}
+	s.inroCancel()
+	<-s.inroDone
+	s.inroCancel = nil
+	s.inroDone = nil
+}
@@ -0,0 +1,442 @@
+package part5
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pascaldekloe/part5/info"
+	"github.com/pascaldekloe/part5/session"
+)
+
+// Cancellation of a large interrogation stops the data stream.
+func TestInroDeact(t *testing.T) {
+	var sys info.System[info.OrigAddr0, info.ComAddr16, info.ObjAddr16]
+	x := Exchange[info.OrigAddr0, info.ComAddr16, info.ObjAddr16]{
+		ComAddr: sys.MustComAddrN(1001),
+	}
+	r := x.Report()
+
+	controlling, controlled := session.Pipe(time.Second)
+	defer close(controlling.Class1)
+	defer close(controlled.Class1)
+
+	const objCount = 10000
+	outstation := NewControlledStation[info.OrigAddr0, info.ComAddr16, info.ObjAddr16](controlled, NewControlDelegate(sys))
+	var sendErr error
+	outstation.Inro = func(c info.Cause, send func(info.DataUnit[info.OrigAddr0, info.ComAddr16, info.ObjAddr16]) error) error {
+		for n := uint(1); n <= objCount; n++ {
+			u, err := r.Object(info.M_SP_NA_1, c, sys.MustObjAddrN(n), []byte{1})
+			if err != nil {
+				return err
+			}
+			if err := send(u); err != nil {
+				sendErr = err
+				return err
+			}
+		}
+		return nil
+	}
+	go outstation.Run()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var received atomic.Int64
+	mon := NewMonitorDelegate(sys)
+	mon.SinglePtMonitor = SinglePtProxy(func(u info.DataUnit[info.OrigAddr0, info.ComAddr16, info.ObjAddr16], _ info.ObjAddr16, _ info.SinglePtQual, _ time.Time) {
+		if u.Cause != info.Inrogen {
+			t.Errorf("got cause %s, want %s", u.Cause, info.Inrogen)
+		}
+		if received.Add(1) == 10 {
+			cancel()
+		}
+	}, time.UTC, 0)
+	station := NewControllingStation[info.OrigAddr0, info.ComAddr16, info.ObjAddr16](controlling, mon)
+	station.Unhandled = func(u info.DataUnit[info.OrigAddr0, info.ComAddr16, info.ObjAddr16], err error) {
+		t.Errorf("unhandled %s: %s", u, err)
+	}
+	go station.Run()
+
+	err := station.Interrogate(ctx, x.Command().Inro())
+	if err != context.Canceled {
+		t.Fatalf("interrogation got error %v, want %v", err, context.Canceled)
+	}
+	if sendErr == nil {
+		t.Error("Inro did not get a send error")
+	}
+
+	// the deactivation confirmation follows the last information object
+	n := received.Load()
+	if n >= objCount {
+		t.Fatal("deactivation did not stop the information objects")
+	}
+
+	// new interrogation runs to completion
+	outstation.Inro = func(c info.Cause, send func(info.DataUnit[info.OrigAddr0, info.ComAddr16, info.ObjAddr16]) error) error {
+		u, err := r.Object(info.M_SP_NA_1, c, sys.MustObjAddrN(1), []byte{1})
+		if err != nil {
+			return err
+		}
+		return send(u)
+	}
+	if err := station.Interrogate(context.Background(), x.Command().Inro()); err != nil {
+		t.Error("second interrogation got error:", err)
+	}
+	// any information object after deactivation precedes the termination
+	if got := received.Load(); got != n+1 {
+		t.Errorf("got %d information objects after deactivation, want 1 from the second interrogation", got-n)
+	}
+}
+
+// Deactivation must pass while class 2 is blocked.
+func TestInroDeactBlocked(t *testing.T) {
+	var sys info.System[info.OrigAddr0, info.ComAddr16, info.ObjAddr16]
+	x := Exchange[info.OrigAddr0, info.ComAddr16, info.ObjAddr16]{
+		ComAddr: sys.MustComAddrN(1001),
+	}
+
+	in := make(chan []byte)
+	class2 := make(chan *session.Outbound)
+	outstation := NewControlledStation[info.OrigAddr0, info.ComAddr16, info.ObjAddr16](&session.Transport{In: in, Class2: class2}, NewControlDelegate(sys))
+	outstation.Inro = func(c info.Cause, send func(info.DataUnit[info.OrigAddr0, info.ComAddr16, info.ObjAddr16]) error) error {
+		for {
+			u, err := x.Report().Object(info.M_SP_NA_1, c, sys.MustObjAddrN(1), []byte{1})
+			if err != nil {
+				return err
+			}
+			if err := send(u); err != nil {
+				return err
+			}
+		}
+	}
+	runDone := make(chan struct{})
+	go func() {
+		defer close(runDone)
+		outstation.Run()
+	}()
+
+	// class 2 is not read while both requests go in
+	act := x.Command().Inro()
+	deact := act
+	deact.Cause = info.Deact
+	in <- act.Append(nil)
+	in <- deact.Append(nil)
+
+	for {
+		u := sys.NewDataUnit()
+		if err := u.Adopt((<-class2).Payload); err != nil {
+			t.Fatal("class 2 parse error:", err)
+		}
+		if u.Cause == info.Deactcon {
+			break
+		}
+		if u.Type != info.C_IC_NA_1 && u.Type != info.M_SP_NA_1 {
+			t.Fatalf("got %s before deactivation confirmation", u)
+		}
+	}
+	close(in)
+	<-runDone
+}
+
+type singleCmdFunc[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr] func(info.DataUnit[Orig, Com, Obj], Obj, info.SinglePt, info.CmdQual)
+
+func (f singleCmdFunc[Orig, Com, Obj]) SingleCmd(u info.DataUnit[Orig, Com, Obj], addr Obj, pt info.SinglePt, q info.CmdQual) {
+	f(u, addr, pt, q)
+}
+
+// Commands beyond the limit must not execute.
+func TestCmdMax(t *testing.T) {
+	var sys info.System[info.OrigAddr0, info.ComAddr16, info.ObjAddr16]
+	cmd := Exchange[info.OrigAddr0, info.ComAddr16, info.ObjAddr16]{
+		ComAddr: sys.MustComAddrN(1001),
+	}.Command()
+
+	controlling, controlled := session.Pipe(time.Second)
+	defer close(controlled.Class1)
+
+	var running, runMax atomic.Int64
+	release := make(chan struct{})
+	ctl := NewControlDelegate(sys)
+	ctl.SingleCmdController = singleCmdFunc[info.OrigAddr0, info.ComAddr16, info.ObjAddr16](
+		func(u info.DataUnit[info.OrigAddr0, info.ComAddr16, info.ObjAddr16], _ info.ObjAddr16, _ info.SinglePt, _ info.CmdQual) {
+			n := running.Add(1)
+			for {
+				max := runMax.Load()
+				if n <= max || runMax.CompareAndSwap(max, n) {
+					break
+				}
+			}
+			<-release
+			running.Add(-1)
+		})
+	outstation := NewControlledStation[info.OrigAddr0, info.ComAddr16, info.ObjAddr16](controlled, ctl)
+	outstation.CmdMax = 2
+	runDone := make(chan struct{})
+	go func() {
+		defer close(runDone)
+		outstation.Run()
+	}()
+
+	var reqs []info.DataUnit[info.OrigAddr0, info.ComAddr16, info.ObjAddr16]
+	for n := uint(1); n <= 4; n++ {
+		req := cmd.SingleCmd(sys.MustObjAddrN(n), info.On, 0)
+		reqs = append(reqs, req)
+		controlling.Class1 <- session.NewOutbound(req.Append(nil))
+	}
+
+	// commands 3 and 4 rejected while 1 and 2 execute, in any order
+	for range reqs[2:] {
+		u := sys.NewDataUnit()
+		if err := u.Adopt(<-controlling.In); err != nil {
+			t.Fatal("response parse error:", err)
+		}
+		req := reqs[3]
+		if u.Info[0] == reqs[2].Info[0] {
+			req = reqs[2]
+		}
+		if err := ConOf(u, req); err != ErrConNeg {
+			t.Errorf("command to address %d got error %v, want %v", req.Info[0], err, ErrConNeg)
+		}
+	}
+
+	close(release)
+	close(controlling.Class1)
+	<-runDone
+	if got := runMax.Load(); got != 2 {
+		t.Errorf("got %d commands in parallel, want 2", got)
+	}
+}
+
+// A blocked class 1 must not hold back the Run routine.
+func TestCmdMaxBlocked(t *testing.T) {
+	var sys info.System[info.OrigAddr0, info.ComAddr16, info.ObjAddr16]
+	x := Exchange[info.OrigAddr0, info.ComAddr16, info.ObjAddr16]{
+		ComAddr: sys.MustComAddrN(1001),
+	}
+
+	in := make(chan []byte)
+	class1 := make(chan *session.Outbound) // not read until the end
+	class2 := make(chan *session.Outbound, 1)
+
+	release := make(chan struct{})
+	ctl := NewControlDelegate(sys)
+	ctl.SingleCmdController = singleCmdFunc[info.OrigAddr0, info.ComAddr16, info.ObjAddr16](
+		func(info.DataUnit[info.OrigAddr0, info.ComAddr16, info.ObjAddr16], info.ObjAddr16, info.SinglePt, info.CmdQual) {
+			<-release
+		})
+	outstation := NewControlledStation[info.OrigAddr0, info.ComAddr16, info.ObjAddr16](&session.Transport{In: in, Class1: class1, Class2: class2}, ctl)
+	outstation.CmdMax = 1
+	runDone := make(chan struct{})
+	go func() {
+		defer close(runDone)
+		outstation.Run()
+	}()
+
+	// first command executes, second is busy
+	in <- x.Command().SingleCmd(sys.MustObjAddrN(1), info.On, 0).Append(nil)
+	in <- x.Command().SingleCmd(sys.MustObjAddrN(2), info.On, 0).Append(nil)
+	// interrogation without Inro gets a negative confirmation on class 2
+	inro := x.Command().Inro()
+	in <- inro.Append(nil)
+	select {
+	case o := <-class2:
+		u := sys.NewDataUnit()
+		if err := u.Adopt(o.Payload); err != nil {
+			t.Fatal("response parse error:", err)
+		}
+		if err := ConOf(u, inro); err != ErrConNeg {
+			t.Errorf("interrogation got error %v, want %v", err, ErrConNeg)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("interrogation not served while class 1 blocked")
+	}
+
+	close(release)
+	<-class1 // busy confirmation
+	close(in)
+	<-runDone
+}
+
+func TestSendClass(t *testing.T) {
+	var sys info.System[info.OrigAddr0, info.ComAddr16, info.ObjAddr16]
+	r := Exchange[info.OrigAddr0, info.ComAddr16, info.ObjAddr16]{
+		ComAddr: sys.MustComAddrN(1001),
+	}.Report()
+
+	class1 := make(chan *session.Outbound, 4)
+	class2 := make(chan *session.Outbound, 4)
+	outstation := NewControlledStation[info.OrigAddr0, info.ComAddr16, info.ObjAddr16](&session.Transport{Class1: class1, Class2: class2}, NewControlDelegate(sys))
+
+	spont, err := r.Object(info.M_SP_NA_1, info.Spont, sys.MustObjAddrN(1), []byte{1})
+	if err != nil {
+		t.Fatal("M_SP_NA_1 build error:", err)
+	}
+	cyclic, err := r.Object(info.M_ME_NC_1, info.Cyclic, sys.MustObjAddrN(2), []byte{0, 0, 0, 0, 0})
+	if err != nil {
+		t.Fatal("M_ME_NC_1 build error:", err)
+	}
+
+	ctx := context.Background()
+	// defaults
+	if err := outstation.Send(ctx, spont); err != nil {
+		t.Fatal("spontaneous send error:", err)
+	}
+	if err := outstation.Send(ctx, cyclic); err != nil {
+		t.Fatal("cyclic send error:", err)
+	}
+	// overrides
+	if err := outstation.SendClass(ctx, spont, 2); err != nil {
+		t.Fatal("spontaneous send on class 2 error:", err)
+	}
+	if err := outstation.SendClass(ctx, cyclic, 1); err != nil {
+		t.Fatal("cyclic send on class 1 error:", err)
+	}
+	if err := outstation.SendClass(ctx, spont, 3); err != errClass {
+		t.Errorf("send on class 3 got error %v, want %v", err, errClass)
+	}
+
+	for i, want := range []info.DataUnit[info.OrigAddr0, info.ComAddr16, info.ObjAddr16]{spont, cyclic} {
+		if got := string((<-class1).Payload); got != string(want.Append(nil)) {
+			t.Errorf("class 1 datagram %d got %#x, want %#x", i, got, want.Append(nil))
+		}
+	}
+	for i, want := range []info.DataUnit[info.OrigAddr0, info.ComAddr16, info.ObjAddr16]{cyclic, spont} {
+		if got := string((<-class2).Payload); got != string(want.Append(nil)) {
+			t.Errorf("class 2 datagram %d got %#x, want %#x", i, got, want.Append(nil))
+		}
+	}
+}
+
+func TestRead(t *testing.T) {
+	var sys info.System[info.OrigAddr0, info.ComAddr16, info.ObjAddr16]
+	x := Exchange[info.OrigAddr0, info.ComAddr16, info.ObjAddr16]{
+		ComAddr: sys.MustComAddrN(1001),
+	}
+	r := x.Report()
+
+	controlling, controlled := session.Pipe(time.Second)
+	defer close(controlling.Class1)
+
+	outstation := NewControlledStation[info.OrigAddr0, info.ComAddr16, info.ObjAddr16](controlled, NewControlDelegate(sys))
+	outstation.Read = func(addr info.ObjAddr16, c info.Cause) (info.DataUnit[info.OrigAddr0, info.ComAddr16, info.ObjAddr16], bool) {
+		if addr.N() != 42 {
+			return info.DataUnit[info.OrigAddr0, info.ComAddr16, info.ObjAddr16]{}, false
+		}
+		u, err := r.Object(info.M_ME_NC_1, c, addr, []byte{0, 0, 0xc0, 0x3f, 0})
+		if err != nil {
+			// not the test routine
+			t.Error("M_ME_NC_1 build error:", err)
+			return u, false
+		}
+		return u, true
+	}
+	go outstation.Run()
+	defer close(controlled.Class1)
+
+	// responses are not ordered; one request at a time
+	req := x.Command().Read(sys.MustObjAddrN(42))
+	controlling.Class1 <- session.NewOutbound(req.Append(nil))
+	res := sys.NewDataUnit()
+	if err := res.Adopt(<-controlling.In); err != nil {
+		t.Fatal("response parse error:", err)
+	}
+	if res.Type != info.M_ME_NC_1 || res.Cause != info.Req {
+		t.Errorf("read of known address got %s, want M_ME_NC_1 with cause %s", res, info.Req)
+	}
+	var got float32
+	mon := NewMonitorDelegate(sys)
+	mon.FloatMonitor = FloatProxy(func(_ info.DataUnit[info.OrigAddr0, info.ComAddr16, info.ObjAddr16], _ info.ObjAddr16, f float32, _ info.Qual, _ time.Time) {
+		got = f
+	}, time.UTC, 0)
+	if err := MonitorDataUnit[info.OrigAddr0, info.ComAddr16, info.ObjAddr16](mon, res); err != nil || got != 1.5 {
+		t.Errorf("read of known address got value %g and error %v, want 1.5", got, err)
+	}
+
+	req = x.Command().Read(sys.MustObjAddrN(99))
+	controlling.Class1 <- session.NewOutbound(req.Append(nil))
+	if err := res.Adopt(<-controlling.In); err != nil {
+		t.Fatal("response parse error:", err)
+	}
+	if res.Type != info.C_RD_NA_1 || res.Cause != info.UnkInfo|info.NegFlag {
+		t.Errorf("read of unknown address got %s, want C_RD_NA_1 rejection with %s", res, info.UnkInfo)
+	}
+}
+
+func TestInitEnd(t *testing.T) {
+	var sys info.System[info.OrigAddr0, info.ComAddr16, info.ObjAddr16]
+
+	class1 := make(chan *session.Outbound)
+	outstation := NewControlledStation[info.OrigAddr0, info.ComAddr16, info.ObjAddr16](&session.Transport{Class1: class1}, NewControlDelegate(sys))
+	outstation.InitAddrs = []info.ComAddr16{sys.MustComAddrN(1001), sys.MustComAddrN(1002)}
+	outstation.InitCause = info.AfterChangeInitCause(0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := outstation.SetLevel(ctx, session.Down); err != nil {
+		t.Fatal("level down got error:", err)
+	}
+	errc := make(chan error)
+	go func() { errc <- outstation.SetLevel(ctx, session.Up) }()
+
+	var got []info.DataUnit[info.OrigAddr0, info.ComAddr16, info.ObjAddr16]
+	var gotCauses []info.InitCause
+	mon := NewMonitorDelegate(sys)
+	mon.InitEndMonitor = initEndFunc[info.OrigAddr0, info.ComAddr16, info.ObjAddr16](func(u info.DataUnit[info.OrigAddr0, info.ComAddr16, info.ObjAddr16], c info.InitCause) {
+		got = append(got, u)
+		gotCauses = append(gotCauses, c)
+	})
+	receive := func() {
+		u := sys.NewDataUnit()
+		if err := u.Adopt((<-class1).Payload); err != nil {
+			t.Fatal("parse error:", err)
+		}
+		if err := MonitorDataUnit[info.OrigAddr0, info.ComAddr16, info.ObjAddr16](mon, u); err != nil {
+			t.Fatal("monitor error:", err)
+		}
+	}
+	receive()
+	// second one cancelled
+	cancel()
+	if err := <-errc; err != context.Canceled {
+		t.Fatalf("level up got error %v, want %v", err, context.Canceled)
+	}
+
+	// remainder on the next Up only
+	levels := make(chan session.Level, 3)
+	levels <- session.Down
+	levels <- session.Up
+	levels <- session.Up
+	close(levels)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		outstation.FollowLevel(levels)
+	}()
+	receive()
+	select {
+	case <-done:
+		break
+	case <-class1:
+		t.Error("end of initialization sent again")
+		<-done
+	}
+
+	for i, u := range got {
+		if want := uint(1001 + i); u.Type != info.M_EI_NA_1 || u.Cause != info.Init || u.Addr.N() != want {
+			t.Errorf("got %s, want M_EI_NA_1 with cause %s on common address %d", u, info.Init, want)
+		}
+		if want := info.AfterChangeInitCause(0); gotCauses[i] != want {
+			t.Errorf("got initialization cause %#x, want %#x", gotCauses[i], want)
+		}
+	}
+	if len(got) != 2 {
+		t.Errorf("got %d ends of initialization, want 2", len(got))
+	}
+}
+
+type initEndFunc[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr] func(info.DataUnit[Orig, Com, Obj], info.InitCause)
+
+func (f initEndFunc[Orig, Com, Obj]) InitEnd(u info.DataUnit[Orig, Com, Obj], c info.InitCause) {
+	f(u, c)
+}
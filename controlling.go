@@ -0,0 +1,353 @@
+package part5
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pascaldekloe/part5/info"
+	"github.com/pascaldekloe/part5/session"
+)
+
+// ErrCmdRace rejects a command while another command to the same information
+// object is in progress.
+var ErrCmdRace = errors.New("part5: command to information object in progress")
+
+// CmdRaceError is ErrCmdRace with the command in progress, which matches with
+// errors.Is.
+type CmdRaceError[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr] struct {
+	Orig Orig        // originator address
+	Com  Com         // common address
+	Addr Obj         // information-object address
+	Type info.TypeID // type identification of the command in progress
+}
+
+// Error implements the builtin.error interface.
+func (e CmdRaceError[Orig, Com, Obj]) Error() string {
+	return fmt.Sprintf("part5: %s command to information object %d at common address %d in progress",
+		e.Type, e.Addr.N(), e.Com.N())
+}
+
+// Unwrap returns ErrCmdRace for errors.Is.
+func (e CmdRaceError[Orig, Com, Obj]) Unwrap() error { return ErrCmdRace }
+
+// CmdKey identifies the target of a command. The originator address is part of
+// the key, such that multiple operators can share a connection.
+type cmdKey[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr] struct {
+	orig Orig
+	com  Com
+	obj  Obj
+}
+
+// KeyOf returns false when the payload has no information-object address.
+func keyOf[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr](u *info.DataUnit[Orig, Com, Obj]) (key cmdKey[Orig, Com, Obj], ok bool) {
+	if len(u.Info) < len(key.obj) {
+		return key, false
+	}
+	key.orig = u.Orig
+	key.com = u.Addr
+	key.obj = Obj(u.Info[:len(key.obj)])
+	return key, true
+}
+
+// A pendingCmd awaits its response.
+type pendingCmd[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr] struct {
+	req info.DataUnit[Orig, Com, Obj]
+	res chan info.DataUnit[Orig, Com, Obj]
+
+	awaitTerm bool // whether res includes termination
+}
+
+// ControllingStation issues commands over a session, and it passes all other
+// information to a Monitor. Commands to distinct information objects proceed
+// in parallel. Commands to the same information object are rejected with
+// ErrCmdRace, which protects select-before-operate sequences against
+// interleaving, with a CmdRaceError.
+type ControllingStation[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr] struct {
+	transport *session.Transport
+	mon       Monitor[Orig, Com, Obj]
+
+	// Unhandled gets called for each inbound ASDU which is neither a
+	// response to a command in progress nor accepted by the Monitor, when
+	// not nil. Confirmations and rejections without a command in progress
+	// go to Unhandled with ErrOtherCmd, and never to the Monitor.
+	Unhandled func(info.DataUnit[Orig, Com, Obj], error)
+
+	// CmdTerm gets called for each command termination [info.Actterm]
+	// received, when not nil. Termination is optional, and it usually
+	// arrives after Exec returned. Interrogate consumes its termination,
+	// including one which crosses a deactivation.
+	// Terminations go to Unhandled with ErrTerm when CmdTerm is nil.
+	CmdTerm func(info.DataUnit[Orig, Com, Obj])
+
+	// DeactTimeout limits the wait for a deactivation confirmation, once
+	// the context of an interrogation is done. Zero defaults to 15 s.
+	DeactTimeout time.Duration
+
+	mutex   sync.Mutex
+	pending map[cmdKey[Orig, Com, Obj]]*pendingCmd[Orig, Com, Obj]
+}
+
+// NewControllingStation returns a new station which operates on transport t.
+// Run must be called to receive.
+func NewControllingStation[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr](t *session.Transport, mon Monitor[Orig, Com, Obj]) *ControllingStation[Orig, Com, Obj] {
+	return &ControllingStation[Orig, Com, Obj]{
+		transport: t,
+		mon:       mon,
+		pending:   make(map[cmdKey[Orig, Com, Obj]]*pendingCmd[Orig, Com, Obj]),
+	}
+}
+
+// Run reads the inbound of the transport until closed.
+func (s *ControllingStation[Orig, Com, Obj]) Run() {
+	var sys info.System[Orig, Com, Obj]
+	for payload := range s.transport.In {
+		u := sys.NewDataUnit()
+		if err := u.Adopt(payload); err != nil {
+			s.unhandled(u, err)
+			continue
+		}
+
+		if s.respond(u) {
+			continue
+		}
+		// responses never go to the Monitor
+		switch c := u.Cause &^ (info.TestFlag | info.NegFlag); {
+		case c == info.Actterm:
+			s.term(u)
+			continue
+		case c == info.Actcon, c == info.Deactcon,
+			c >= info.UnkType && c <= info.UnkInfo:
+			s.unhandled(u, ErrOtherCmd) // late or orphaned
+			continue
+		}
+		if err := MonitorDataUnit(s.mon, u); err != nil {
+			s.unhandled(u, err)
+		}
+	}
+}
+
+func (s *ControllingStation[Orig, Com, Obj]) unhandled(u info.DataUnit[Orig, Com, Obj], err error) {
+	if s.Unhandled != nil {
+		s.Unhandled(u, err)
+	}
+}
+
+func (s *ControllingStation[Orig, Com, Obj]) term(u info.DataUnit[Orig, Com, Obj]) {
+	if s.CmdTerm != nil {
+		s.CmdTerm(u)
+	} else {
+		s.unhandled(u, ErrTerm)
+	}
+}
+
+// Respond passes u to the command in progress, if any.
+func (s *ControllingStation[Orig, Com, Obj]) respond(u info.DataUnit[Orig, Com, Obj]) bool {
+	key, ok := keyOf(&u)
+	if !ok {
+		return false
+	}
+
+	s.mutex.Lock()
+	p, ok := s.pending[key]
+	ok = ok && p.req.Type == u.Type &&
+		(p.awaitTerm || u.Cause&^info.TestFlag != info.Actterm)
+	s.mutex.Unlock()
+	if !ok {
+		return false
+	}
+
+	select {
+	case p.res <- u:
+		return true
+	default:
+		return false // response pending already
+	}
+}
+
+// Acquire claims the information object of req.
+func (s *ControllingStation[Orig, Com, Obj]) acquire(req info.DataUnit[Orig, Com, Obj], awaitTerm bool) (*pendingCmd[Orig, Com, Obj], error) {
+	key, ok := keyOf(&req)
+	if !ok {
+		return nil, errInfoSize
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if p, ok := s.pending[key]; ok {
+		return nil, CmdRaceError[Orig, Com, Obj]{key.orig, key.com, key.obj, p.req.Type}
+	}
+	p := &pendingCmd[Orig, Com, Obj]{
+		req: req,
+		// confirmation and termination may arrive back to back
+		res: make(chan info.DataUnit[Orig, Com, Obj], 2),
+
+		awaitTerm: awaitTerm,
+	}
+	s.pending[key] = p
+	return p, nil
+}
+
+// Release frees the information object of p.
+func (s *ControllingStation[Orig, Com, Obj]) release(p *pendingCmd[Orig, Com, Obj]) {
+	key, _ := keyOf(&p.req)
+	s.mutex.Lock()
+	delete(s.pending, key)
+	s.mutex.Unlock()
+}
+
+// Exec sends the command request, and it awaits its confirmation. The error
+// is conform ConOf on response. Requests which fail VerifyQual are not sent.
+func (s *ControllingStation[Orig, Com, Obj]) Exec(ctx context.Context, req info.DataUnit[Orig, Com, Obj]) error {
+	if err := VerifyQual(req); err != nil {
+		return err
+	}
+	p, err := s.acquire(req, false)
+	if err != nil {
+		return err
+	}
+	defer s.release(p)
+	return s.exchange(ctx, p)
+}
+
+// SelectExecute sends the command request with the S/E flag set first, and
+// without the S/E flag once the selection is confirmed. No other command to
+// the information object can interleave. See chapter 6.8: “Command
+// transmission” of section 5.
+func (s *ControllingStation[Orig, Com, Obj]) SelectExecute(ctx context.Context, req info.DataUnit[Orig, Com, Obj]) error {
+	var addr Obj
+	qi, ok := qualIndex(req.Type)
+	if !ok {
+		return errNoSelect
+	}
+	qi += len(addr)
+	if req.Enc != 1 || len(req.Info) <= qi {
+		return errSingleObj
+	}
+	if err := VerifyQual(req); err != nil {
+		return err
+	}
+
+	sel := req
+	sel.Info = append([]byte(nil), req.Info...)
+	sel.Info[qi] |= 0x80
+	exec := req
+	exec.Info = append([]byte(nil), req.Info...)
+	exec.Info[qi] &^= 0x80
+
+	p, err := s.acquire(sel, false)
+	if err != nil {
+		return err
+	}
+	defer s.release(p)
+	if err := s.exchange(ctx, p); err != nil {
+		return err
+	}
+	s.mutex.Lock()
+	p.req = exec
+	s.mutex.Unlock()
+	return s.exchange(ctx, p)
+}
+
+// Exchange submits p.req, and it awaits the confirmation.
+func (s *ControllingStation[Orig, Com, Obj]) exchange(ctx context.Context, p *pendingCmd[Orig, Com, Obj]) error {
+	out := session.NewOutbound(p.req.Append(nil))
+	select {
+	case s.transport.Class1 <- out:
+		break
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	done := out.Done
+	for {
+		select {
+		case res := <-p.res:
+			err := ConOf(res, p.req)
+			switch {
+			case err == ErrTerm:
+				continue // interrogation terminated during deactivation
+			case err != nil && p.req.Cause&^info.TestFlag == info.Deact &&
+				res.Cause&^(info.TestFlag|info.NegFlag) == info.Actcon:
+				continue // late activation confirmation
+			}
+			return err
+		case err, ok := <-done:
+			if ok && err != nil {
+				return err
+			}
+			done = nil // delivered
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+var errNotInro = errors.New("part5: interrogation request not C_IC_NA_1")
+
+// Interrogate sends the interrogation request, and it awaits both the
+// confirmation and the termination. The information objects in between go to
+// the Monitor. When ctx is done after submission, then the interrogation gets
+// deactivated, and the return is ctx.Err() once the deactivation is confirmed.
+// See chapter 7.4.5 of companion standard 101.
+func (s *ControllingStation[Orig, Com, Obj]) Interrogate(ctx context.Context, req info.DataUnit[Orig, Com, Obj]) error {
+	if req.Type != info.C_IC_NA_1 {
+		return errNotInro
+	}
+	p, err := s.acquire(req, true)
+	if err != nil {
+		return err
+	}
+	defer s.release(p)
+
+	out := session.NewOutbound(req.Append(nil))
+	select {
+	case s.transport.Class1 <- out:
+		break
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	done := out.Done
+	for {
+		select {
+		case res := <-p.res:
+			switch err := ConOf(res, req); err {
+			case nil:
+				continue // confirmed; await termination
+			case ErrTerm:
+				return nil
+			default:
+				return err
+			}
+		case err, ok := <-done:
+			if ok && err != nil {
+				return err
+			}
+			done = nil // delivered
+		case <-ctx.Done():
+			return s.deactivate(p, ctx.Err())
+		}
+	}
+}
+
+// Deactivate sends the deactivation of p.req, and it awaits the confirmation.
+// The cause is returned on success.
+func (s *ControllingStation[Orig, Com, Obj]) deactivate(p *pendingCmd[Orig, Com, Obj], cause error) error {
+	timeout := s.DeactTimeout
+	if timeout == 0 {
+		timeout = 15 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	s.mutex.Lock()
+	p.req.Cause = info.Deact | p.req.Cause&info.TestFlag
+	s.mutex.Unlock()
+	if err := s.exchange(ctx, p); err != nil {
+		return fmt.Errorf("part5: deactivation on %w: %w", cause, err)
+	}
+	return cause
+}
@@ -0,0 +1,330 @@
+package part5
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pascaldekloe/part5/info"
+	"github.com/pascaldekloe/part5/session"
+)
+
+// Selects to distinct information objects must not wait on each other.
+func TestSelectConcurrency(t *testing.T) {
+	var sys info.System[info.OrigAddr8, info.ComAddr16, info.ObjAddr24]
+	cmd := Exchange[info.OrigAddr8, info.ComAddr16, info.ObjAddr24]{
+		ComAddr: sys.MustComAddrN(1001),
+	}.Command()
+
+	local, remote := session.Pipe(time.Second)
+	station := NewControllingStation(local, NewMonitorDelegate(sys))
+	go station.Run()
+	defer close(remote.Class1)
+	defer close(local.Class1)
+
+	// confirm each selection only after both selections arrived
+	go func() {
+		var sels []info.DataUnit[info.OrigAddr8, info.ComAddr16, info.ObjAddr24]
+		for payload := range remote.In {
+			u := sys.NewDataUnit()
+			if err := u.Adopt(payload); err != nil {
+				t.Error("remote parse error:", err)
+				return
+			}
+			if u.Info[len(u.Info)-1]&0x80 == 0 {
+				remote.Class1 <- session.NewOutbound(ConfirmPositive(u).Append(nil))
+				continue
+			}
+			sels = append(sels, u)
+			if len(sels) == 2 {
+				for _, sel := range sels {
+					remote.Class1 <- session.NewOutbound(ConfirmPositive(sel).Append(nil))
+				}
+			}
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	var wg sync.WaitGroup
+	for _, n := range []uint{100, 200} {
+		wg.Add(1)
+		go func(addr info.ObjAddr24) {
+			defer wg.Done()
+			err := station.SelectExecute(ctx, cmd.SingleCmd(addr, info.On, 0))
+			if err != nil {
+				t.Errorf("address %d got error: %s", addr.N(), err)
+			}
+		}(sys.MustObjAddrN(n))
+	}
+	wg.Wait()
+
+	// same address is rejected while in progress
+	p, err := station.acquire(cmd.SingleCmd(sys.MustObjAddrN(100), info.On, 0), false)
+	if err != nil {
+		t.Fatal("acquire error:", err)
+	}
+	defer station.release(p)
+	err = station.SelectExecute(ctx, cmd.SingleCmd(sys.MustObjAddrN(100), info.Off, 0))
+	if !errors.Is(err, ErrCmdRace) {
+		t.Fatalf("command to address in progress got error %v, want %v", err, ErrCmdRace)
+	}
+	const want = "part5: C_SC_NA_1 command to information object 100 at common address 1001 in progress"
+	if err.Error() != want {
+		t.Errorf("got error %q, want %q", err, want)
+	}
+	var race CmdRaceError[info.OrigAddr8, info.ComAddr16, info.ObjAddr24]
+	if !errors.As(err, &race) || race.Addr.N() != 100 || race.Type != info.C_SC_NA_1 {
+		t.Errorf("got race error %+v, want C_SC_NA_1 to address 100", race)
+	}
+}
+
+// Terminations arrive after the command completed.
+func TestCmdTerm(t *testing.T) {
+	var sys info.System[info.OrigAddr8, info.ComAddr16, info.ObjAddr24]
+	x := Exchange[info.OrigAddr8, info.ComAddr16, info.ObjAddr24]{
+		OrigAddr: sys.MustOrigAddrN(9),
+		ComAddr:  sys.MustComAddrN(1001),
+	}
+
+	local, remote := session.Pipe(time.Second)
+	station := NewControllingStation(local, NewMonitorDelegate(sys))
+	terms := make(chan info.DataUnit[info.OrigAddr8, info.ComAddr16, info.ObjAddr24], 1)
+	station.CmdTerm = func(u info.DataUnit[info.OrigAddr8, info.ComAddr16, info.ObjAddr24]) {
+		terms <- u
+	}
+	go station.Run()
+	defer close(remote.Class1)
+	defer close(local.Class1)
+
+	// confirm with return information, and then terminate
+	go func() {
+		for payload := range remote.In {
+			req := sys.NewDataUnit()
+			if err := req.Adopt(payload); err != nil {
+				t.Error("remote parse error:", err)
+				return
+			}
+			remote.Class1 <- session.NewOutbound(ConfirmPositive(req).Append(nil))
+			remote.Class1 <- session.NewOutbound(Terminate(req).Append(nil))
+		}
+	}()
+
+	req := x.Command().SingleCmd(sys.MustObjAddrN(42), info.On, 0)
+	req.Cause |= info.TestFlag
+	if err := station.Exec(context.Background(), req); err != nil {
+		t.Fatal("command error:", err)
+	}
+
+	select {
+	case u := <-terms:
+		if u.Cause != info.Actterm|info.TestFlag || !u.Mirrors(req) {
+			t.Errorf("got termination %s, want mirror of %s with cause %s", u, req, info.Actterm|info.TestFlag)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no termination")
+	}
+}
+
+// A termination which crosses the deactivation belongs to the interrogation.
+func TestInroDeactTerm(t *testing.T) {
+	var sys info.System[info.OrigAddr8, info.ComAddr16, info.ObjAddr24]
+	x := Exchange[info.OrigAddr8, info.ComAddr16, info.ObjAddr24]{
+		ComAddr: sys.MustComAddrN(1001),
+	}
+
+	local, remote := session.Pipe(time.Second)
+	station := NewControllingStation(local, NewMonitorDelegate(sys))
+	station.CmdTerm = func(u info.DataUnit[info.OrigAddr8, info.ComAddr16, info.ObjAddr24]) {
+		t.Errorf("interrogation termination %s passed to CmdTerm", u)
+	}
+	station.Unhandled = func(u info.DataUnit[info.OrigAddr8, info.ComAddr16, info.ObjAddr24], err error) {
+		t.Errorf("unhandled %s: %s", u, err)
+	}
+	go station.Run()
+	defer close(remote.Class1)
+	defer close(local.Class1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		var act info.DataUnit[info.OrigAddr8, info.ComAddr16, info.ObjAddr24]
+		for payload := range remote.In {
+			req := sys.NewDataUnit()
+			if err := req.Adopt(payload); err != nil {
+				t.Error("remote parse error:", err)
+				return
+			}
+			switch req.Cause {
+			case info.Act:
+				act = req
+				remote.Class1 <- session.NewOutbound(ConfirmPositive(req).Append(nil))
+				cancel() // deactivate
+			case info.Deact:
+				remote.Class1 <- session.NewOutbound(Terminate(act).Append(nil))
+				remote.Class1 <- session.NewOutbound(ConfirmPositive(req).Append(nil))
+			}
+		}
+	}()
+
+	if err := station.Interrogate(ctx, x.Command().Inro()); err != context.Canceled {
+		t.Errorf("got error %v, want %v", err, context.Canceled)
+	}
+}
+
+// Responses without a command in progress must not reach the Monitor.
+func TestStrayResponse(t *testing.T) {
+	var sys info.System[info.OrigAddr8, info.ComAddr16, info.ObjAddr24]
+	r := Exchange[info.OrigAddr8, info.ComAddr16, info.ObjAddr24]{
+		OrigAddr: sys.MustOrigAddrN(9),
+		ComAddr:  sys.MustComAddrN(1001),
+	}.Report()
+
+	local, remote := session.Pipe(time.Second)
+	mon := NewMonitorDelegate(sys)
+	var got []info.Cause
+	spont := make(chan struct{})
+	mon.FloatMonitor = FloatProxy(func(u info.DataUnit[info.OrigAddr8, info.ComAddr16, info.ObjAddr24], _ info.ObjAddr24, _ float32, _ info.Qual, _ time.Time) {
+		got = append(got, u.Cause)
+		if u.Cause == info.Spont {
+			close(spont)
+		}
+	}, time.UTC, 0)
+	station := NewControllingStation(local, mon)
+	var terms, others int
+	station.CmdTerm = func(info.DataUnit[info.OrigAddr8, info.ComAddr16, info.ObjAddr24]) {
+		terms++
+	}
+	station.Unhandled = func(u info.DataUnit[info.OrigAddr8, info.ComAddr16, info.ObjAddr24], err error) {
+		if err != ErrOtherCmd {
+			t.Errorf("unhandled %s got error %v, want %v", u, err, ErrOtherCmd)
+		}
+		others++
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		station.Run()
+	}()
+
+	for _, c := range []info.Cause{info.Actterm, info.Actcon, info.Deactcon | info.NegFlag, info.UnkInfo | info.NegFlag, info.Spont} {
+		u, err := r.Object(info.M_ME_NC_1, c, sys.MustObjAddrN(42), []byte{0, 0, 0x80, 0x3f, 0})
+		if err != nil {
+			t.Fatal("M_ME_NC_1 build error:", err)
+		}
+		remote.Class1 <- session.NewOutbound(u.Append(nil))
+	}
+	// in order of submission
+	select {
+	case <-spont:
+		break
+	case <-time.After(time.Second):
+		t.Fatal("spontaneous information not received")
+	}
+	close(remote.Class1)
+	close(local.Class1)
+	<-done
+
+	if len(got) != 1 || got[0] != info.Spont {
+		t.Errorf("Monitor got causes %s, want only %s", got, info.Spont)
+	}
+	if terms != 1 || others != 3 {
+		t.Errorf("got %d terminations and %d other responses, want 1 and 3", terms, others)
+	}
+}
+
+// Operators on a shared connection get their own confirmations.
+func TestOrigConfirm(t *testing.T) {
+	var sys info.System[info.OrigAddr8, info.ComAddr16, info.ObjAddr24]
+	operators := []Command[info.OrigAddr8, info.ComAddr16, info.ObjAddr24]{
+		Exchange[info.OrigAddr8, info.ComAddr16, info.ObjAddr24]{
+			OrigAddr: sys.MustOrigAddrN(1),
+			ComAddr:  sys.MustComAddrN(1001),
+		}.Command(),
+		Exchange[info.OrigAddr8, info.ComAddr16, info.ObjAddr24]{
+			OrigAddr: sys.MustOrigAddrN(2),
+			ComAddr:  sys.MustComAddrN(1001),
+		}.Command(),
+	}
+
+	local, remote := session.Pipe(time.Second)
+	station := NewControllingStation(local, NewMonitorDelegate(sys))
+	station.Unhandled = func(u info.DataUnit[info.OrigAddr8, info.ComAddr16, info.ObjAddr24], err error) {
+		t.Errorf("unhandled %s: %s", u, err)
+	}
+	go station.Run()
+	defer close(remote.Class1)
+	defer close(local.Class1)
+
+	// confirm in reverse order, negative for originator 2 only
+	go func() {
+		var reqs []info.DataUnit[info.OrigAddr8, info.ComAddr16, info.ObjAddr24]
+		for payload := range remote.In {
+			req := sys.NewDataUnit()
+			if err := req.Adopt(payload); err != nil {
+				t.Error("remote parse error:", err)
+				return
+			}
+			reqs = append(reqs, req)
+			if len(reqs) < 2 {
+				continue
+			}
+			for i := len(reqs) - 1; i >= 0; i-- {
+				res := ConfirmPositive(reqs[i])
+				if reqs[i].Orig.N() == 2 {
+					res = ConfirmNegative(reqs[i])
+				}
+				remote.Class1 <- session.NewOutbound(res.Append(nil))
+			}
+		}
+	}()
+
+	errs := make([]error, len(operators))
+	var wg sync.WaitGroup
+	for i, cmd := range operators {
+		wg.Add(1)
+		go func(i int, cmd Command[info.OrigAddr8, info.ComAddr16, info.ObjAddr24]) {
+			defer wg.Done()
+			// same information object
+			errs[i] = station.Exec(context.Background(), cmd.SingleCmd(sys.MustObjAddrN(42), info.On, 0))
+		}(i, cmd)
+	}
+	wg.Wait()
+
+	if errs[0] != nil {
+		t.Errorf("originator 1 got error %v, want positive confirmation", errs[0])
+	}
+	if errs[1] != ErrConNeg {
+		t.Errorf("originator 2 got error %v, want %v", errs[1], ErrConNeg)
+	}
+}
+
+// Confirmation and termination can arrive before Interrogate reads either.
+func TestRespondBackToBack(t *testing.T) {
+	var sys info.System[info.OrigAddr8, info.ComAddr16, info.ObjAddr24]
+	req := Exchange[info.OrigAddr8, info.ComAddr16, info.ObjAddr24]{
+		ComAddr: sys.MustComAddrN(1001),
+	}.Command().Inro()
+
+	local, _ := session.Pipe(time.Second)
+	station := NewControllingStation(local, NewMonitorDelegate(sys))
+	p, err := station.acquire(req, true)
+	if err != nil {
+		t.Fatal("acquire error:", err)
+	}
+	defer station.release(p)
+
+	if !station.respond(ConfirmPositive(req)) {
+		t.Error("confirmation not passed to the command in progress")
+	}
+	if !station.respond(Terminate(req)) {
+		t.Error("termination not passed to the command in progress")
+	}
+	if got := <-p.res; got.Cause != info.Actcon {
+		t.Errorf("first response got cause %s, want %s", got.Cause, info.Actcon)
+	}
+	if got := <-p.res; got.Cause != info.Actterm {
+		t.Errorf("second response got cause %s, want %s", got.Cause, info.Actterm)
+	}
+}
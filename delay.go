@@ -0,0 +1,169 @@
+package part5
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/pascaldekloe/part5/info"
+)
+
+// DelayCorrection subtracts the transmission delay from the info.CP24Time2a
+// and info.CP56Time2a tags. The delay is usually the value of the last delay
+// acquisition command [C_CD_NA_1], conform chapter 7.3.4.7 of companion
+// standard 101. All calls pass to the next Monitor, with corrected time tags.
+type DelayCorrection[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr] struct {
+	Monitor[Orig, Com, Obj] // next
+
+	loc   *time.Location
+	delay atomic.Int64 // milliseconds
+}
+
+// NewDelayCorrection returns a new correction which passes to next. Time tags
+// are reconstructed in loc, the time-zone of the peer. The delay is zero until
+// SetDelay.
+func NewDelayCorrection[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr](next Monitor[Orig, Com, Obj], loc *time.Location) *DelayCorrection[Orig, Com, Obj] {
+	return &DelayCorrection[Orig, Com, Obj]{Monitor: next, loc: loc}
+}
+
+// SetDelay replaces the transmission delay. SetDelay is safe for concurrent use.
+func (delay *DelayCorrection[Orig, Com, Obj]) SetDelay(d info.CP16Time2a) {
+	delay.delay.Store(int64(d.Millis()))
+}
+
+// Delay returns the transmission delay in use.
+func (delay *DelayCorrection[Orig, Com, Obj]) Delay() time.Duration {
+	return time.Duration(delay.delay.Load()) * time.Millisecond
+}
+
+// ShiftCP24 returns tag minus the delay, within the hour. Flags, including IV,
+// are preserved.
+func (delay *DelayCorrection[Orig, Com, Obj]) shiftCP24(tag info.CP24Time2a) info.CP24Time2a {
+	d := delay.delay.Load()
+	if d == 0 || tag.Invalid() {
+		return tag
+	}
+	min, ms := tag.MinuteAndMillis()
+	const hourMillis = 60 * 60 * 1000
+	n := (int64(min)*60*1000 + int64(ms) - d%hourMillis + hourMillis) % hourMillis
+	tag[0] = byte(n % 60000)
+	tag[1] = byte(n % 60000 >> 8)
+	tag[2] = tag[2]&^0x3f | byte(n/60000)
+	return tag
+}
+
+// ShiftCP56 returns tag minus the delay. Reserved bits are preserved. The day
+// of the week and the summer-time flag are set only when tag has a day of the
+// week.
+func (delay *DelayCorrection[Orig, Com, Obj]) shiftCP56(tag info.CP56Time2a) info.CP56Time2a {
+	d := delay.delay.Load()
+	if d == 0 || tag.Invalid() {
+		return tag
+	}
+	t := tag.Within20thCentury(delay.loc).Add(-time.Duration(d) * time.Millisecond)
+
+	var shifted info.CP56Time2a
+	if tag[4]>>5 != 0 {
+		shifted.Set(t)
+	} else {
+		shifted.SetNotAll(t)
+		shifted[3] |= tag[3] & 0x80 // SU
+	}
+	shifted[2] |= tag[2] & 0x40 // RES1
+	shifted[3] |= tag[3] & 0x60 // RES2
+	shifted[5] |= tag[5] & 0xf0 // RES3
+	shifted[6] |= tag[6] & 0x80 // RES4
+	return shifted
+}
+
+func (delay *DelayCorrection[Orig, Com, Obj]) SinglePtAtMinute(u info.DataUnit[Orig, Com, Obj], addr Obj, p info.SinglePtQual, tag info.CP24Time2a) {
+	delay.Monitor.SinglePtAtMinute(u, addr, p, delay.shiftCP24(tag))
+}
+
+func (delay *DelayCorrection[Orig, Com, Obj]) SinglePtAtMoment(u info.DataUnit[Orig, Com, Obj], addr Obj, p info.SinglePtQual, tag info.CP56Time2a) {
+	delay.Monitor.SinglePtAtMoment(u, addr, p, delay.shiftCP56(tag))
+}
+
+func (delay *DelayCorrection[Orig, Com, Obj]) DoublePtAtMinute(u info.DataUnit[Orig, Com, Obj], addr Obj, p info.DoublePtQual, tag info.CP24Time2a) {
+	delay.Monitor.DoublePtAtMinute(u, addr, p, delay.shiftCP24(tag))
+}
+
+func (delay *DelayCorrection[Orig, Com, Obj]) DoublePtAtMoment(u info.DataUnit[Orig, Com, Obj], addr Obj, p info.DoublePtQual, tag info.CP56Time2a) {
+	delay.Monitor.DoublePtAtMoment(u, addr, p, delay.shiftCP56(tag))
+}
+
+func (delay *DelayCorrection[Orig, Com, Obj]) StepAtMinute(u info.DataUnit[Orig, Com, Obj], addr Obj, p info.StepQual, tag info.CP24Time2a) {
+	delay.Monitor.StepAtMinute(u, addr, p, delay.shiftCP24(tag))
+}
+
+func (delay *DelayCorrection[Orig, Com, Obj]) StepAtMoment(u info.DataUnit[Orig, Com, Obj], addr Obj, p info.StepQual, tag info.CP56Time2a) {
+	delay.Monitor.StepAtMoment(u, addr, p, delay.shiftCP56(tag))
+}
+
+func (delay *DelayCorrection[Orig, Com, Obj]) BitsAtMinute(u info.DataUnit[Orig, Com, Obj], addr Obj, b info.BitsQual, tag info.CP24Time2a) {
+	delay.Monitor.BitsAtMinute(u, addr, b, delay.shiftCP24(tag))
+}
+
+func (delay *DelayCorrection[Orig, Com, Obj]) BitsAtMoment(u info.DataUnit[Orig, Com, Obj], addr Obj, b info.BitsQual, tag info.CP56Time2a) {
+	delay.Monitor.BitsAtMoment(u, addr, b, delay.shiftCP56(tag))
+}
+
+func (delay *DelayCorrection[Orig, Com, Obj]) NormAtMinute(u info.DataUnit[Orig, Com, Obj], addr Obj, n info.NormQual, tag info.CP24Time2a) {
+	delay.Monitor.NormAtMinute(u, addr, n, delay.shiftCP24(tag))
+}
+
+func (delay *DelayCorrection[Orig, Com, Obj]) NormAtMoment(u info.DataUnit[Orig, Com, Obj], addr Obj, n info.NormQual, tag info.CP56Time2a) {
+	delay.Monitor.NormAtMoment(u, addr, n, delay.shiftCP56(tag))
+}
+
+func (delay *DelayCorrection[Orig, Com, Obj]) ScaledAtMinute(u info.DataUnit[Orig, Com, Obj], addr Obj, v int16, q info.Qual, tag info.CP24Time2a) {
+	delay.Monitor.ScaledAtMinute(u, addr, v, q, delay.shiftCP24(tag))
+}
+
+func (delay *DelayCorrection[Orig, Com, Obj]) ScaledAtMoment(u info.DataUnit[Orig, Com, Obj], addr Obj, v int16, q info.Qual, tag info.CP56Time2a) {
+	delay.Monitor.ScaledAtMoment(u, addr, v, q, delay.shiftCP56(tag))
+}
+
+func (delay *DelayCorrection[Orig, Com, Obj]) FloatAtMinute(u info.DataUnit[Orig, Com, Obj], addr Obj, f float32, q info.Qual, tag info.CP24Time2a) {
+	delay.Monitor.FloatAtMinute(u, addr, f, q, delay.shiftCP24(tag))
+}
+
+func (delay *DelayCorrection[Orig, Com, Obj]) FloatAtMoment(u info.DataUnit[Orig, Com, Obj], addr Obj, f float32, q info.Qual, tag info.CP56Time2a) {
+	delay.Monitor.FloatAtMoment(u, addr, f, q, delay.shiftCP56(tag))
+}
+
+func (delay *DelayCorrection[Orig, Com, Obj]) TotalsAtMinute(u info.DataUnit[Orig, Com, Obj], addr Obj, c info.Counter, tag info.CP24Time2a) {
+	delay.Monitor.TotalsAtMinute(u, addr, c, delay.shiftCP24(tag))
+}
+
+func (delay *DelayCorrection[Orig, Com, Obj]) TotalsAtMoment(u info.DataUnit[Orig, Com, Obj], addr Obj, c info.Counter, tag info.CP56Time2a) {
+	delay.Monitor.TotalsAtMoment(u, addr, c, delay.shiftCP56(tag))
+}
+
+func (delay *DelayCorrection[Orig, Com, Obj]) ProtectAtMinute(u info.DataUnit[Orig, Com, Obj], addr Obj, e info.ProtectEvent, tag info.CP24Time2a) {
+	delay.Monitor.ProtectAtMinute(u, addr, e, delay.shiftCP24(tag))
+}
+
+func (delay *DelayCorrection[Orig, Com, Obj]) ProtectAtMoment(u info.DataUnit[Orig, Com, Obj], addr Obj, e info.ProtectEvent, tag info.CP56Time2a) {
+	delay.Monitor.ProtectAtMoment(u, addr, e, delay.shiftCP56(tag))
+}
+
+func (delay *DelayCorrection[Orig, Com, Obj]) ProtectStartAtMinute(u info.DataUnit[Orig, Com, Obj], addr Obj, e info.ProtectStartEvent, tag info.CP24Time2a) {
+	delay.Monitor.ProtectStartAtMinute(u, addr, e, delay.shiftCP24(tag))
+}
+
+func (delay *DelayCorrection[Orig, Com, Obj]) ProtectStartAtMoment(u info.DataUnit[Orig, Com, Obj], addr Obj, e info.ProtectStartEvent, tag info.CP56Time2a) {
+	delay.Monitor.ProtectStartAtMoment(u, addr, e, delay.shiftCP56(tag))
+}
+
+func (delay *DelayCorrection[Orig, Com, Obj]) ProtectOutAtMinute(u info.DataUnit[Orig, Com, Obj], addr Obj, e info.ProtectOutEvent, tag info.CP24Time2a) {
+	delay.Monitor.ProtectOutAtMinute(u, addr, e, delay.shiftCP24(tag))
+}
+
+func (delay *DelayCorrection[Orig, Com, Obj]) ProtectOutAtMoment(u info.DataUnit[Orig, Com, Obj], addr Obj, e info.ProtectOutEvent, tag info.CP56Time2a) {
+	delay.Monitor.ProtectOutAtMoment(u, addr, e, delay.shiftCP56(tag))
+}
+
+// Private implements the PrivateMonitor interface.
+func (delay *DelayCorrection[Orig, Com, Obj]) Private(u info.DataUnit[Orig, Com, Obj]) error {
+	return monitorPrivate(delay.Monitor, u)
+}
@@ -0,0 +1,56 @@
+package part5
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pascaldekloe/part5/info"
+)
+
+func TestDelayCorrection(t *testing.T) {
+	var sys info.System[info.OrigAddr0, info.ComAddr8, info.ObjAddr16]
+	r := testExchange().Report()
+
+	var got []time.Time
+	mon := NewMonitorDelegate(sys)
+	mon.FloatMonitor = FloatProxy(func(_ info.DataUnit[info.OrigAddr0, info.ComAddr8, info.ObjAddr16], _ info.ObjAddr16, _ float32, _ info.Qual, t time.Time) {
+		got = append(got, t)
+	}, time.UTC, time.Hour)
+
+	delay := NewDelayCorrection[info.OrigAddr0, info.ComAddr8, info.ObjAddr16](mon, time.UTC)
+	delay.SetDelay(info.CP16Time2a{0xdc, 0x05}) // 1500 ms
+	if got := delay.Delay(); got != 1500*time.Millisecond {
+		t.Fatalf("got delay %s, want 1.5s", got)
+	}
+
+	// minute zero with 1 second wraps to the previous hour
+	cp24 := info.CP24Time2a{0xe8, 0x03, 0x40} // with RES1
+	u, err := r.Object(info.M_ME_TC_1, info.Spont, sys.MustObjAddrN(1), append([]byte{0, 0, 0, 0, 0}, cp24[:]...))
+	if err != nil {
+		t.Fatal("M_ME_TC_1 build error:", err)
+	}
+	if err := MonitorDataUnit[info.OrigAddr0, info.ComAddr8, info.ObjAddr16](delay, u); err != nil {
+		t.Fatal("monitor error:", err)
+	}
+
+	// midnight wraps to the previous day
+	var cp56 info.CP56Time2a
+	cp56.Set(time.Date(2024, 3, 1, 0, 0, 1, 0, time.UTC))
+	u, err = r.Object(info.M_ME_TF_1, info.Spont, sys.MustObjAddrN(2), append([]byte{0, 0, 0, 0, 0}, cp56[:]...))
+	if err != nil {
+		t.Fatal("M_ME_TF_1 build error:", err)
+	}
+	if err := MonitorDataUnit[info.OrigAddr0, info.ComAddr8, info.ObjAddr16](delay, u); err != nil {
+		t.Fatal("monitor error:", err)
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("got %d time tags, want 2", len(got))
+	}
+	if min, sec, nsec := got[0].Minute(), got[0].Second(), got[0].Nanosecond(); min != 59 || sec != 59 || nsec != 500e6 {
+		t.Errorf("CP24 got %s, want minute 59 with 59.5 s", got[0])
+	}
+	if want := time.Date(2024, 2, 29, 23, 59, 59, 500e6, time.UTC); !got[1].Equal(want) {
+		t.Errorf("CP56 got %s, want %s", got[1], want)
+	}
+}
@@ -0,0 +1,144 @@
+package part5
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pascaldekloe/part5/info"
+)
+
+// ClockDrift estimates the clock offset of a peer from the info.CP56Time2a tags
+// on spontaneous information. The tags are compared against the local time of
+// arrival. A wrong clock in the controlled station, despite clock
+// synchronization, also misleads the reconstruction of info.CP24Time2a tags.
+// All calls pass to the next Monitor as is.
+type ClockDrift[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr] struct {
+	Monitor[Orig, Com, Obj] // next
+
+	clock     func() time.Time
+	loc       *time.Location
+	threshold time.Duration
+	warn      func(offset time.Duration)
+
+	mutex    sync.Mutex
+	offset   time.Duration // running estimate
+	samples  int
+	exceeded bool
+}
+
+// NewClockDrift returns a new estimation which passes to next. The clock
+// defaults to time.Now when nil. Time tags are reconstructed in loc, the
+// time-zone of the peer. Warn gets called each time the offset estimate
+// exceeds the threshold, in either direction, after it was within.
+func NewClockDrift[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr](next Monitor[Orig, Com, Obj], clock func() time.Time, loc *time.Location, threshold time.Duration, warn func(offset time.Duration)) *ClockDrift[Orig, Com, Obj] {
+	if clock == nil {
+		clock = time.Now
+	}
+	return &ClockDrift[Orig, Com, Obj]{
+		Monitor:   next,
+		clock:     clock,
+		loc:       loc,
+		threshold: threshold,
+		warn:      warn,
+	}
+}
+
+// Offset returns the estimated time of the peer clock minus the local time.
+// Transmission delay makes the estimate somewhat negative. The boolean is
+// false when no time tag was seen yet.
+func (drift *ClockDrift[Orig, Com, Obj]) Offset() (time.Duration, bool) {
+	drift.mutex.Lock()
+	defer drift.mutex.Unlock()
+	return drift.offset, drift.samples != 0
+}
+
+// Observe updates the estimate with an exponentially weighted moving average.
+// Information from interrogation or background scan may be old, and it is
+// ignored as such.
+func (drift *ClockDrift[Orig, Com, Obj]) observe(u info.DataUnit[Orig, Com, Obj], tag info.CP56Time2a) {
+	if u.Cause&^(info.NegFlag|info.TestFlag) != info.Spont {
+		return
+	}
+	t := tag.Within20thCentury(drift.loc)
+	if t.IsZero() {
+		return // invalid
+	}
+	sample := t.Sub(drift.clock())
+
+	drift.mutex.Lock()
+	if drift.samples == 0 {
+		drift.offset = sample
+	} else {
+		drift.offset += (sample - drift.offset) / 8
+	}
+	drift.samples++
+	offset := drift.offset
+	exceeded := offset > drift.threshold || offset < -drift.threshold
+	warn := exceeded && !drift.exceeded
+	drift.exceeded = exceeded
+	drift.mutex.Unlock()
+
+	if warn && drift.warn != nil {
+		drift.warn(offset)
+	}
+}
+
+func (drift *ClockDrift[Orig, Com, Obj]) SinglePtAtMoment(u info.DataUnit[Orig, Com, Obj], addr Obj, p info.SinglePtQual, tag info.CP56Time2a) {
+	drift.observe(u, tag)
+	drift.Monitor.SinglePtAtMoment(u, addr, p, tag)
+}
+
+func (drift *ClockDrift[Orig, Com, Obj]) DoublePtAtMoment(u info.DataUnit[Orig, Com, Obj], addr Obj, p info.DoublePtQual, tag info.CP56Time2a) {
+	drift.observe(u, tag)
+	drift.Monitor.DoublePtAtMoment(u, addr, p, tag)
+}
+
+func (drift *ClockDrift[Orig, Com, Obj]) StepAtMoment(u info.DataUnit[Orig, Com, Obj], addr Obj, p info.StepQual, tag info.CP56Time2a) {
+	drift.observe(u, tag)
+	drift.Monitor.StepAtMoment(u, addr, p, tag)
+}
+
+func (drift *ClockDrift[Orig, Com, Obj]) BitsAtMoment(u info.DataUnit[Orig, Com, Obj], addr Obj, b info.BitsQual, tag info.CP56Time2a) {
+	drift.observe(u, tag)
+	drift.Monitor.BitsAtMoment(u, addr, b, tag)
+}
+
+func (drift *ClockDrift[Orig, Com, Obj]) NormAtMoment(u info.DataUnit[Orig, Com, Obj], addr Obj, n info.NormQual, tag info.CP56Time2a) {
+	drift.observe(u, tag)
+	drift.Monitor.NormAtMoment(u, addr, n, tag)
+}
+
+func (drift *ClockDrift[Orig, Com, Obj]) ScaledAtMoment(u info.DataUnit[Orig, Com, Obj], addr Obj, v int16, q info.Qual, tag info.CP56Time2a) {
+	drift.observe(u, tag)
+	drift.Monitor.ScaledAtMoment(u, addr, v, q, tag)
+}
+
+func (drift *ClockDrift[Orig, Com, Obj]) FloatAtMoment(u info.DataUnit[Orig, Com, Obj], addr Obj, f float32, q info.Qual, tag info.CP56Time2a) {
+	drift.observe(u, tag)
+	drift.Monitor.FloatAtMoment(u, addr, f, q, tag)
+}
+
+func (drift *ClockDrift[Orig, Com, Obj]) TotalsAtMoment(u info.DataUnit[Orig, Com, Obj], addr Obj, c info.Counter, tag info.CP56Time2a) {
+	drift.observe(u, tag)
+	drift.Monitor.TotalsAtMoment(u, addr, c, tag)
+}
+
+func (drift *ClockDrift[Orig, Com, Obj]) ProtectAtMoment(u info.DataUnit[Orig, Com, Obj], addr Obj, e info.ProtectEvent, tag info.CP56Time2a) {
+	drift.observe(u, tag)
+	drift.Monitor.ProtectAtMoment(u, addr, e, tag)
+}
+
+func (drift *ClockDrift[Orig, Com, Obj]) ProtectStartAtMoment(u info.DataUnit[Orig, Com, Obj], addr Obj, e info.ProtectStartEvent, tag info.CP56Time2a) {
+	drift.observe(u, tag)
+	drift.Monitor.ProtectStartAtMoment(u, addr, e, tag)
+}
+
+func (drift *ClockDrift[Orig, Com, Obj]) ProtectOutAtMoment(u info.DataUnit[Orig, Com, Obj], addr Obj, e info.ProtectOutEvent, tag info.CP56Time2a) {
+	drift.observe(u, tag)
+	drift.Monitor.ProtectOutAtMoment(u, addr, e, tag)
+}
+
+// Private implements the PrivateMonitor interface.
+func (drift *ClockDrift[Orig, Com, Obj]) Private(u info.DataUnit[Orig, Com, Obj]) error {
+	return monitorPrivate(drift.Monitor, u)
+}
@@ -0,0 +1,60 @@
+package part5
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/pascaldekloe/part5/info"
+)
+
+func TestClockDrift(t *testing.T) {
+	var sys info.System[info.OrigAddr0, info.ComAddr8, info.ObjAddr16]
+	r := testExchange().Report()
+
+	now := time.Date(2024, 2, 29, 13, 14, 15, 0, time.UTC)
+	const peerOffset = 3 * time.Second
+
+	var warnings []time.Duration
+	drift := NewClockDrift(NewLogger(sys, io.Discard), func() time.Time { return now }, time.UTC, time.Second, func(offset time.Duration) {
+		warnings = append(warnings, offset)
+	})
+	if _, ok := drift.Offset(); ok {
+		t.Error("got offset before any time tag")
+	}
+
+	// interrogated information may be old
+	var tag info.CP56Time2a
+	tag.Set(now.Add(-time.Hour))
+	u, err := r.Object(info.M_SP_TB_1, info.Inrogen, sys.MustObjAddrN(1), append([]byte{1}, tag[:]...))
+	if err != nil {
+		t.Fatal("M_SP_TB_1 build error:", err)
+	}
+	if err := MonitorDataUnit[info.OrigAddr0, info.ComAddr8, info.ObjAddr16](drift, u); err != nil {
+		t.Fatal("monitor error:", err)
+	}
+	if _, ok := drift.Offset(); ok {
+		t.Error("got offset from interrogated information")
+	}
+
+	for i := 0; i < 10; i++ {
+		now = now.Add(time.Minute)
+		// transmission delay varies in milliseconds
+		tag.Set(now.Add(peerOffset - time.Duration(i%3)*time.Millisecond))
+		u, err := r.Object(info.M_ME_TF_1, info.Spont, sys.MustObjAddrN(2), append([]byte{0, 0, 0, 0, 0}, tag[:]...))
+		if err != nil {
+			t.Fatal("M_ME_TF_1 build error:", err)
+		}
+		if err := MonitorDataUnit[info.OrigAddr0, info.ComAddr8, info.ObjAddr16](drift, u); err != nil {
+			t.Fatal("monitor error:", err)
+		}
+	}
+
+	offset, ok := drift.Offset()
+	if !ok || offset < peerOffset-3*time.Millisecond || offset > peerOffset {
+		t.Errorf("got offset %s, want about %s", offset, peerOffset)
+	}
+	if len(warnings) != 1 || warnings[0] != peerOffset {
+		t.Errorf("got warnings %s, want [%s]", warnings, peerOffset)
+	}
+}
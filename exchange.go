@@ -2,6 +2,7 @@ package part5
 
 import (
 	"encoding/binary"
+	"errors"
 	"math"
 
 	"github.com/pascaldekloe/part5/info"
@@ -28,6 +29,194 @@ func (x Exchange[Orig, Com, Obj]) NewDataUnit(t info.TypeID, e info.Enc, c info.
 	return u
 }
 
+// Validate errors on ASDUs which are not fit for submission. They must come
+// with a type identification outside of the reserved ranges, and with a cause
+// of transmission. The common address must be the one of the Exchange, or the
+// global address on the broadcast commands from chapter 7.2.4 of companion
+// standard 101. The originator address must be the one of the Exchange, unless
+// the Exchange has none [zero], as with responses which mirror the originator
+// of a request. Causes are checked against the type, conform table 14 of
+// companion standard 101, with NegFlag restricted to confirmations and
+// rejections. The direction is checked by the Validate of Command and Report
+// only. Finally, the address sequence [SQ] must be permitted for the type, and
+// the size of Info must match the variable structure qualifier on types with a
+// fixed element size. Private types (128..255) pass without checks on cause,
+// sequence and size.
+func (x Exchange[Orig, Com, Obj]) Validate(u info.DataUnit[Orig, Com, Obj]) error {
+	switch {
+	case u.Type == 0:
+		return info.ErrTypeZero
+	case reservedType(u.Type):
+		return errTypeReserve
+	case u.Cause&^(info.TestFlag|info.NegFlag) == 0:
+		return info.ErrCauseZero
+	case u.Addr.N() == 0:
+		return info.ErrComAddrZero
+	case u.Addr.Global():
+		if !allowsGlobalAddr(u.Type) {
+			return errGlobalAddr
+		}
+	case u.Addr != x.ComAddr:
+		return errComAddr
+	}
+	if x.OrigAddr.N() != 0 && u.Orig != x.OrigAddr {
+		return errOrigAddr
+	}
+	if u.Type >= 128 {
+		return nil // private range
+	}
+
+	if !causeFits(u.Type, u.Cause) {
+		return errCauseType
+	}
+
+	if u.Enc.AddrSeq() && !info.AllowsSequence(u.Type) {
+		// variable structure qualifier at offset 1
+		return info.DecodeError{Type: u.Type, Offset: 1, Reason: info.ErrAddrSeqType}
+	}
+	elemSize, ok := info.ElemSize(u.Type)
+	if !ok {
+		return nil // variable size
+	}
+	if u.Enc.AddrSeq() && u.Enc.Count() != 0 {
+		_, err := addrSeqStart(&u, elemSize)
+		return err
+	}
+	var addr Obj
+	if len(u.Info) != u.Enc.Count()*(len(addr)+elemSize) {
+		return payloadErr(&u, errInfoSize)
+	}
+	return nil
+}
+
+// Validate is like Exchange Validate, plus it requires the cause of
+// transmission to be in control direction, i.e., activation, deactivation, or
+// the request of a read command. File transfer and private types pass without
+// a check on the direction.
+func (cmd Command[Orig, Com, Obj]) Validate(u info.DataUnit[Orig, Com, Obj]) error {
+	if err := cmd.Exchange.Validate(u); err != nil {
+		return err
+	}
+	if u.Type < info.F_FR_NA_1 && !controlCause(u.Type, u.Cause) {
+		return errCauseDir
+	}
+	return nil
+}
+
+// Validate is like Exchange Validate, plus it requires the cause of
+// transmission to be in monitor direction, i.e., anything but activation,
+// deactivation, or the request of a read command. File transfer and private
+// types pass without a check on the direction.
+func (r Report[Orig, Com, Obj]) Validate(u info.DataUnit[Orig, Com, Obj]) error {
+	if err := r.Exchange.Validate(u); err != nil {
+		return err
+	}
+	if u.Type < info.F_FR_NA_1 && controlCause(u.Type, u.Cause) {
+		return errCauseDir
+	}
+	return nil
+}
+
+// Validate has errors in addition to errInfoSize.
+var (
+	errTypeReserve = errors.New("part5: type identification reserved for further compatible definitions")
+	errComAddr     = errors.New("part5: common address differs from the exchange")
+	errGlobalAddr  = errors.New("part5: global common address not permitted for type identification")
+	errOrigAddr    = errors.New("part5: originator address differs from the exchange")
+	errCauseType   = errors.New("part5: cause of transmission not permitted for type identification")
+	errCauseDir    = errors.New("part5: cause of transmission not permitted in the direction")
+)
+
+// ReservedType returns whether t is reserved for further compatible definitions,
+// conform chapter 7.2.1 of companion standard 101, and section 7.
+func reservedType(t info.TypeID) bool {
+	switch {
+	case t > info.M_ME_ND_1 && t < info.M_SP_TB_1,
+		t > info.S_IT_TC_1 && t < info.C_SC_NA_1,
+		t > info.C_BO_NA_1 && t < info.C_SC_TA_1,
+		t > info.C_BO_TA_1 && t < info.M_EI_NA_1,
+		t > info.M_EI_NA_1 && t < info.S_CH_NA_1,
+		t > info.S_ER_NA_1 && t < info.S_US_NA_1,
+		t > info.S_UC_NA_1 && t < info.C_IC_NA_1,
+		t > info.C_TS_TA_1 && t < info.P_ME_NA_1,
+		t > info.P_AC_NA_1 && t < info.F_FR_NA_1:
+		return true
+	}
+	return false
+}
+
+// ControlCause returns whether cause c of type t is in control direction.
+func controlCause(t info.TypeID, c info.Cause) bool {
+	switch c &^ (info.TestFlag | info.NegFlag) {
+	case info.Act, info.Deact:
+		return true
+	case info.Req:
+		return t == info.C_RD_NA_1
+	}
+	return false
+}
+
+// AllowsGlobalAddr returns whether the type is a broadcast command.
+func allowsGlobalAddr(t info.TypeID) bool {
+	switch t {
+	case info.C_IC_NA_1, info.C_CI_NA_1, info.C_CS_NA_1, info.C_RP_NA_1:
+		return true
+	}
+	return false
+}
+
+// CauseFits returns whether the cause is permitted for the type identification.
+// Types without a definition in table 14 of companion standard 101 pass.
+func causeFits(t info.TypeID, c info.Cause) bool {
+	neg := c&info.NegFlag != 0
+	c &^= info.TestFlag | info.NegFlag
+	if c >= info.UnkType && c <= info.UnkInfo {
+		// rejection of any request
+		return t >= info.C_SC_NA_1 && t != info.M_EI_NA_1
+	}
+
+	switch {
+	case t < info.C_SC_NA_1:
+		// monitor information
+		switch {
+		case c == info.Cyclic, c == info.Back, c == info.Spont,
+			c == info.Req, c == info.Retrem, c == info.Retloc,
+			c >= info.Inrogen && c <= info.Reqco4:
+			return !neg
+		}
+		return false
+
+	case t == info.M_EI_NA_1:
+		return c == info.Init && !neg
+
+	case t == info.C_RD_NA_1:
+		return c == info.Req && !neg
+
+	case t <= info.C_BO_TA_1, t >= info.C_IC_NA_1 && t <= info.C_TS_TA_1:
+		// commands
+		switch c {
+		case info.Act, info.Deact, info.Actterm:
+			return !neg
+		case info.Actcon, info.Deactcon:
+			return true
+		}
+		return false
+
+	case t >= info.P_ME_NA_1 && t <= info.P_AC_NA_1:
+		// parameters
+		switch {
+		case c == info.Act, c == info.Deact:
+			return !neg
+		case c == info.Actcon, c == info.Deactcon:
+			return true
+		case c >= info.Inrogen && c <= info.Inro16:
+			return !neg && t != info.P_AC_NA_1
+		}
+		return false
+	}
+	return true
+}
+
 // Command has the controlling perspective of an Exchange.
 type Command[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr] struct {
 	Exchange[Orig, Com, Obj]
@@ -129,3 +318,158 @@ func (cmd Command[Orig, Com, Obj]) TestCmd() info.DataUnit[Orig, Com, Obj] {
 	u.Info = append(u.Info, 0b1010_1010, 0b0101_0101)
 	return u
 }
+
+// DelayAcq returns delay acquisition command: C_CD_NA_1 act(ivation),
+// conform chapter 7.3.4.7 of companion standard 101. The delay sets
+// the transmission delay in the controlled station.
+func (cmd Command[Orig, Com, Obj]) DelayAcq(delay info.CP16Time2a) info.DataUnit[Orig, Com, Obj] {
+	var addr Obj // fixed to zero
+	u := cmd.act(info.C_CD_NA_1, addr)
+	u.Info = append(u.Info, delay[0], delay[1])
+	return u
+}
+
+// ClockSync returns clock synchronization command: C_CS_NA_1 act(ivation),
+// conform chapter 7.3.4.4 of companion standard 101.
+func (cmd Command[Orig, Com, Obj]) ClockSync(tag info.CP56Time2a) info.DataUnit[Orig, Com, Obj] {
+	var addr Obj // fixed to zero
+	u := cmd.act(info.C_CS_NA_1, addr)
+	u.Info = append(u.Info, tag[:]...)
+	return u
+}
+
+// Read returns read command: C_RD_NA_1 req(uest),
+// conform chapter 7.3.4.3 of companion standard 101.
+func (cmd Command[Orig, Com, Obj]) Read(addr Obj) info.DataUnit[Orig, Com, Obj] {
+	u := cmd.Exchange.NewDataUnit(info.C_RD_NA_1, 1, info.Req)
+	for i := 0; i < len(addr); i++ {
+		u.Info = append(u.Info, addr[i])
+	}
+	return u
+}
+
+// Report has the monitoring perspective of an Exchange.
+type Report[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr] struct {
+	Exchange[Orig, Com, Obj]
+
+	// Information-object address zero is irrelevant in the standard, and
+	// thus rejected with info.ErrObjAddrZero on types which need a relevant
+	// address. Some installations do put measured values on address zero
+	// nonetheless. ZeroObjAddr permits such use for all type identifiers.
+	ZeroObjAddr bool
+}
+
+// Report returns the monitoring perspective of an Exchange.
+func (x Exchange[Orig, Com, Obj]) Report() Report[Orig, Com, Obj] {
+	return Report[Orig, Com, Obj]{Exchange: x}
+}
+
+func (r Report[Orig, Com, Obj]) checkObjAddr(t info.TypeID, addr Obj) error {
+	if addr.N() == 0 && !r.ZeroObjAddr && !info.AllowsIrrelevantAddr(t) {
+		return info.ErrObjAddrZero
+	}
+	return nil
+}
+
+// Object returns a single information object of type t. The element must have
+// the encoding of type t, including any time tag. The irrelevant address, i.e.,
+// zero, is rejected with info.ErrObjAddrZero unless permitted. See ZeroObjAddr.
+func (r Report[Orig, Com, Obj]) Object(t info.TypeID, c info.Cause, addr Obj, element []byte) (info.DataUnit[Orig, Com, Obj], error) {
+	if err := r.checkObjAddr(t, addr); err != nil {
+		return info.DataUnit[Orig, Com, Obj]{}, err
+	}
+
+	u := r.Exchange.NewDataUnit(t, 1, c)
+	for i := 0; i < len(addr); i++ {
+		u.Info = append(u.Info, addr[i])
+	}
+	u.Info = append(u.Info, element...)
+	return u, nil
+}
+
+var errObjCount = errors.New("part5: number of information objects exceeds 127")
+
+// Seq returns information objects of type t with an address sequence, i.e.,
+// with the SQ flag set, starting at the first address. Each element must have
+// the encoding of type t. Types which do not permit the SQ flag are rejected
+// with info.ErrAddrSeqType. Address overflow is rejected with info.ErrAddrSeq.
+// The first address is checked the same way as with Object.
+func (r Report[Orig, Com, Obj]) Seq(t info.TypeID, c info.Cause, first Obj, elements ...[]byte) (info.DataUnit[Orig, Com, Obj], error) {
+	if !info.AllowsSequence(t) {
+		return info.DataUnit[Orig, Com, Obj]{}, info.ErrAddrSeqType
+	}
+	if err := r.checkObjAddr(t, first); err != nil {
+		return info.DataUnit[Orig, Com, Obj]{}, err
+	}
+	if len(elements) > 127 {
+		return info.DataUnit[Orig, Com, Obj]{}, errObjCount
+	}
+	if len(elements) != 0 {
+		lastN := first.N() + uint(len(elements)) - 1
+		if _, ok := r.System.ObjAddrN(lastN); !ok {
+			return info.DataUnit[Orig, Com, Obj]{}, info.ErrAddrSeq
+		}
+	}
+
+	// SQ flag plus object count
+	u := r.Exchange.NewDataUnit(t, info.Enc(len(elements))|0x80, c)
+	for i := 0; i < len(first); i++ {
+		u.Info = append(u.Info, first[i])
+	}
+	for _, e := range elements {
+		u.Info = append(u.Info, e...)
+	}
+	return u, nil
+}
+
+// Retrem returns the status of the information object addressed by a command
+// request, with cause info.Retrem, i.e., return information caused by a remote
+// command. The TestFlag from the request is preserved. Type t and the element
+// are for the information object in monitor direction. Retrem should follow the
+// confirmation of the command. See chapter 7.2.3 of companion standard 101.
+func (r Report[Orig, Com, Obj]) Retrem(req info.DataUnit[Orig, Com, Obj], t info.TypeID, element []byte) (info.DataUnit[Orig, Com, Obj], error) {
+	var addr Obj
+	if req.Enc != 1 || len(req.Info) < len(addr) {
+		return info.DataUnit[Orig, Com, Obj]{}, errSingleObj
+	}
+	addr = Obj(req.Info[:len(addr)])
+	return r.Object(t, info.Retrem|req.Cause&info.TestFlag, addr, element)
+}
+
+// Retloc returns the status of an information object with cause info.Retloc,
+// i.e., return information caused by a local command, such as operation from
+// the control panel of the controlled station.
+func (r Report[Orig, Com, Obj]) Retloc(t info.TypeID, addr Obj, element []byte) (info.DataUnit[Orig, Com, Obj], error) {
+	return r.Object(t, info.Retloc, addr, element)
+}
+
+// InitEnd returns end of initialization: M_EI_NA_1 init(ialized), conform
+// chapter 7.3.3.1 of companion standard 101.
+func (r Report[Orig, Com, Obj]) InitEnd(c info.InitCause) info.DataUnit[Orig, Com, Obj] {
+	var addr Obj // fixed to zero
+	u := r.Exchange.NewDataUnit(info.M_EI_NA_1, 1, info.Init)
+	for i := 0; i < len(addr); i++ {
+		u.Info = append(u.Info, addr[i])
+	}
+	u.Info = append(u.Info, byte(c))
+	return u
+}
+
+// ConfirmOnly returns the responses to a command request which reports the
+// current state of the addressed object instead of executing, in order of
+// submission: the positive confirmation, the return information with cause
+// info.Retrem, and the termination with info.Actterm. Deactivation requests get
+// no termination. The type t and the element are for the information object in
+// monitor direction. Local operation goes with Retloc instead. See chapter
+// 7.2.3 of companion standard 101.
+func (r Report[Orig, Com, Obj]) ConfirmOnly(req info.DataUnit[Orig, Com, Obj], t info.TypeID, element []byte) ([]info.DataUnit[Orig, Com, Obj], error) {
+	status, err := r.Retrem(req, t, element)
+	if err != nil {
+		return nil, err
+	}
+	responses := []info.DataUnit[Orig, Com, Obj]{ConfirmPositive(req), status}
+	if req.Cause&^info.TestFlag == info.Act {
+		responses = append(responses, Terminate(req))
+	}
+	return responses, nil
+}
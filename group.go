@@ -0,0 +1,63 @@
+package part5
+
+import "github.com/pascaldekloe/part5/info"
+
+// InroGroups maps information-object addresses to interrogation groups, conform
+// chapter 7.2.6.22 of companion standard 101. Each address is a member of the
+// station interrogation, and of at most one group in range 1..16. The zero
+// value has no group assignments. InroGroups is not safe for concurrent use
+// with Assign or AssignRange.
+type InroGroups[Obj info.ObjAddr] struct {
+	groups map[Obj]uint8
+}
+
+// Assign sets the group of each address, replacing any previous assignment.
+// Group zero removes the assignment, i.e., station interrogation only. Assign
+// panics on groups beyond 16.
+func (g *InroGroups[Obj]) Assign(group uint, addrs ...Obj) {
+	if group > 16 {
+		panic("part5: interrogation group not in range 1..16")
+	}
+	if g.groups == nil {
+		g.groups = make(map[Obj]uint8)
+	}
+	for _, addr := range addrs {
+		if group == 0 {
+			delete(g.groups, addr)
+		} else {
+			g.groups[addr] = uint8(group)
+		}
+	}
+}
+
+// AssignRange sets the group of each address from first up to and including
+// last, with Assign semantics.
+func (g *InroGroups[Obj]) AssignRange(group uint, first, last Obj) {
+	var sys info.System[info.OrigAddr0, info.ComAddr8, Obj]
+	for n := first.N(); n <= last.N(); n++ {
+		// within range of first and last
+		addr, _ := sys.ObjAddrN(n)
+		g.Assign(group, addr)
+	}
+}
+
+// Group returns the interrogation group of addr in range 1..16, or zero for
+// station interrogation only.
+func (g *InroGroups[Obj]) Group(addr Obj) uint {
+	return uint(g.groups[addr])
+}
+
+// Member returns whether addr is included in the interrogation of cause c, with
+// info.Inrogen for all addresses, and info.Inro1 up to and including info.Inro16
+// for group members only. Any TestFlag in c is ignored.
+func (g *InroGroups[Obj]) Member(addr Obj, c info.Cause) bool {
+	c &^= info.TestFlag
+	switch {
+	case c == info.Inrogen:
+		return true
+	case c >= info.Inro1 && c <= info.Inro16:
+		return g.Group(addr) == uint(c-info.Inro1)+1
+	default:
+		return false
+	}
+}
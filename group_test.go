@@ -0,0 +1,39 @@
+package part5
+
+import (
+	"testing"
+
+	"github.com/pascaldekloe/part5/info"
+)
+
+func TestInroGroups(t *testing.T) {
+	var sys info.System[info.OrigAddr0, info.ComAddr8, info.ObjAddr16]
+
+	var g InroGroups[info.ObjAddr16]
+	g.Assign(2, sys.MustObjAddrN(7), sys.MustObjAddrN(9))
+	g.AssignRange(16, sys.MustObjAddrN(250), sys.MustObjAddrN(260))
+	g.Assign(0, sys.MustObjAddrN(255))
+
+	golden := []struct {
+		addr  uint
+		group uint
+	}{
+		{1, 0}, {7, 2}, {8, 0}, {9, 2},
+		{249, 0}, {250, 16}, {254, 16}, {255, 0}, {256, 16}, {260, 16}, {261, 0},
+	}
+	for _, gold := range golden {
+		addr := sys.MustObjAddrN(gold.addr)
+		if got := g.Group(addr); got != gold.group {
+			t.Errorf("address %d got group %d, want %d", gold.addr, got, gold.group)
+		}
+		if !g.Member(addr, info.Inrogen|info.TestFlag) {
+			t.Errorf("address %d not a member of station interrogation", gold.addr)
+		}
+		for c := info.Inro1; c <= info.Inro16; c++ {
+			want := gold.group != 0 && c == info.Inro1+info.Cause(gold.group-1)
+			if got := g.Member(addr, c); got != want {
+				t.Errorf("address %d got membership %t for %s, want %t", gold.addr, got, c, want)
+			}
+		}
+	}
+}
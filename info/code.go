@@ -6,7 +6,7 @@ import "errors"
 
 // ErrTypeZero denies the zero value as type identification. Use is explicitly
 // prohibited in chapter 7.2.1.1 of companion standard 101.
-var errTypeZero = errors.New("part5: type identification <0> is not used")
+var ErrTypeZero = errors.New("part5: type identification <0> is not used")
 
 // TypeID classifies ASDU with a code.
 type TypeID uint8
@@ -460,7 +460,7 @@ type Cause uint8
 
 // ErrCauseZero denies the zero value as a cause code. Use is explicitly
 // prohibited in table 14 from companion standard 101.
-var errCauseZero = errors.New("part5: cause of transmission <0> is not used")
+var ErrCauseZero = errors.New("part5: cause of transmission <0> is not used")
 
 // The cause of transmission codes are defined in table 14 from companion
 // standard 101. Auth, Seskey and Usrkey are defined in table 1 from section 7,
@@ -26,7 +26,7 @@ type System[Orig OrigAddr, Com ComAddr, Obj ObjAddr] struct{}
 
 // ErrComAddrZero denies the zero value as an address. Use is explicitly
 // prohibited in chapter 7.2.4 of companion standard 101.
-var errComAddrZero = errors.New("part5: common address <0> is not used")
+var ErrComAddrZero = errors.New("part5: common address <0> is not used")
 
 type (
 	// ComAddr can be instantiated with ComAddrN of System.
@@ -108,7 +108,8 @@ type (
 		ObjAddr8 | ObjAddr16 | ObjAddr24
 
 		// N gets the address as a numeric value.
-		// Zero marks the address as irrelevant.
+		// Zero marks the address as irrelevant, which is
+		// not legal on all types. See AllowsIrrelevantAddr.
 		N() uint
 	}
 
@@ -569,6 +570,71 @@ func (c Counter) Invalid() bool { return c[4]&uint8(IV) != 0 }
 // FlagInvalid sets the IV flag.
 func (c *Counter) FlagInvalid() { c[4] |= uint8(IV) }
 
+// Freeze applies mode f to the running counter c, and it returns the reading
+// for transmission. The sequence number increments on each freeze. A reset
+// zeroes the count, and it clears the Carry flag while setting the Adjusted
+// flag, conform chapter 7.2.6.23 of companion standard 101.
+func (c *Counter) Freeze(f FreezeMode) Counter {
+	switch f {
+	case FreezeRead:
+		break // reading as is
+	case FreezeKeep:
+		c.SetSeqNo(c.SeqNo() + 1)
+	case FreezeReset:
+		c.SetSeqNo(c.SeqNo() + 1)
+		reading := *c
+		c.reset()
+		return reading
+	case FreezeResetOnly:
+		c.reset()
+	}
+	return *c
+}
+
+// Reset zeroes the count with the CA flag, keeping the sequence number.
+func (c *Counter) reset() {
+	c.SetCount(0)
+	c[4] = c[4]&^32 | 64
+}
+
+// FreezeCounters applies mode f to each running counter, and it returns the
+// readings for transmission. See Counter Freeze for details.
+func FreezeCounters(f FreezeMode, running []Counter) (readings []Counter) {
+	readings = make([]Counter, len(running))
+	for i := range running {
+		readings[i] = running[i].Freeze(f)
+	}
+	return readings
+}
+
+// CounterQual is the qualifier of counter interrogation (QCC) conform chapter
+// 7.2.6.23 of companion standard 101.
+type CounterQual uint8
+
+// NewCounterQual returns the qualifier with request in range 0..63, and with
+// freeze mode f. Request 0 is for no counter, 1..4 request a group, and 5 is
+// the general request counter.
+func NewCounterQual(request uint, f FreezeMode) CounterQual {
+	return CounterQual(request&63) | CounterQual(f&3)<<6
+}
+
+// Request returns the RQT in range 0..63.
+func (q CounterQual) Request() uint { return uint(q & 63) }
+
+// Freeze returns the FRZ.
+func (q CounterQual) Freeze() FreezeMode { return FreezeMode(q >> 6) }
+
+// FreezeMode is the FRZ from a CounterQual.
+type FreezeMode uint8
+
+// Freeze modes of counter interrogation.
+const (
+	FreezeRead      FreezeMode = iota // read only; no freeze nor reset
+	FreezeKeep                        // counter freeze without reset
+	FreezeReset                       // counter freeze with reset
+	FreezeResetOnly                   // counter reset
+)
+
 // ProtectEvent reports state from protection equipment conform chapter
 // 7.2.6.10 of companion standard 101.
 type ProtectEvent [3]uint8
@@ -66,3 +66,46 @@ func TestNorm(t *testing.T) {
 		f = got
 	}
 }
+
+func TestCounterFreeze(t *testing.T) {
+	var c Counter
+	c.SetCount(1234)
+	c.SetSeqNo(31)
+
+	got := c.Freeze(FreezeKeep)
+	if got.Count() != 1234 || got.SeqNo() != 0 || got.Adjusted() {
+		t.Errorf("freeze without reset got count %d, sequence number %d and CA %t; want 1234, 0 and false",
+			got.Count(), got.SeqNo(), got.Adjusted())
+	}
+	if c != got {
+		t.Errorf("freeze without reset got running counter %#x, want %#x", c, got)
+	}
+
+	c.SetCarry()
+	got = c.Freeze(FreezeReset)
+	if got.Count() != 1234 || got.SeqNo() != 1 || !got.Carry() {
+		t.Errorf("freeze with reset got reading count %d, sequence number %d and CY %t; want 1234, 1 and true",
+			got.Count(), got.SeqNo(), got.Carry())
+	}
+	if c.Count() != 0 || !c.Adjusted() || c.Carry() || c.SeqNo() != 1 {
+		t.Errorf("freeze with reset got running count %d, CA %t, CY %t and sequence number %d; want 0, true, false and 1",
+			c.Count(), c.Adjusted(), c.Carry(), c.SeqNo())
+	}
+
+	readings := FreezeCounters(FreezeRead, []Counter{c})
+	if len(readings) != 1 || readings[0] != c {
+		t.Errorf("read got %#x, want [%#x]", readings, c)
+	}
+
+	c.SetCount(99)
+	got = c.Freeze(FreezeResetOnly)
+	if got.Count() != 0 || got.SeqNo() != 1 || !got.Adjusted() || c != got {
+		t.Errorf("reset without freeze got count %d, sequence number %d and CA %t; want 0, 1 and true",
+			got.Count(), got.SeqNo(), got.Adjusted())
+	}
+
+	q := NewCounterQual(5, FreezeReset)
+	if q.Request() != 5 || q.Freeze() != FreezeReset {
+		t.Errorf("qualifier %#x got request %d and freeze %d, want 5 and %d", q, q.Request(), q.Freeze(), FreezeReset)
+	}
+}
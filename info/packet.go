@@ -2,7 +2,9 @@ package info
 
 import (
 	"errors"
+	"fmt"
 	"io"
+	"strings"
 )
 
 // Enc is the variable-structure qualifier (VQL), which defines the payload
@@ -22,6 +24,194 @@ func (e Enc) AddrSeq() bool { return e&0x80 != 0 }
 // behaviour with the ObjAddr width.
 var ErrAddrSeq = errors.New("part5: address sequence [VQL SQ] overflows the addres space for information objects")
 
+// ErrAddrSeqType rejects a packet encoding with the SQ flag on a type which
+// does not permit address sequences. See AllowsSequence.
+var ErrAddrSeqType = errors.New("part5: address sequence [VQL SQ] not allowed for type identifier")
+
+// AllowsSequence returns whether the SQ flag of Enc may be set for the type.
+// Only information without a time tag permits address sequences, conform the
+// type definitions in chapter 7.3 of companion standard 101.
+func AllowsSequence(t TypeID) bool { return seqTypes[t] }
+
+var seqTypes = [256]bool{
+	M_SP_NA_1: true,
+	M_DP_NA_1: true,
+	M_ST_NA_1: true,
+	M_BO_NA_1: true,
+	M_ME_NA_1: true,
+	M_ME_NB_1: true,
+	M_ME_NC_1: true,
+	M_IT_NA_1: true,
+	M_PS_NA_1: true,
+	M_ME_ND_1: true,
+}
+
+// ErrObjAddrZero rejects the irrelevant information-object address on a type
+// which needs a relevant one. See AllowsIrrelevantAddr.
+var ErrObjAddrZero = errors.New("part5: information object address zero not allowed for type identifier")
+
+// AllowsIrrelevantAddr returns whether the type identifier permits (or even
+// requires) information-object address zero, i.e., the irrelevant address.
+//
+// “The value 0 of the information object address is reserved for the case
+// when the information object address is not relevant.”
+// — chapter 7.2.5 of companion standard 101
+func AllowsIrrelevantAddr(t TypeID) bool { return irrelevantAddrTypes[t] }
+
+var irrelevantAddrTypes = [256]bool{
+	M_EI_NA_1: true,
+	C_IC_NA_1: true,
+	C_CI_NA_1: true,
+	C_CS_NA_1: true,
+	C_TS_NA_1: true,
+	C_RP_NA_1: true,
+	C_CD_NA_1: true,
+	C_TS_TA_1: true,
+}
+
+// ElemSize returns the number of octets of an information object, excluding
+// its address, i.e., the information element(s) plus any time tag. The return
+// is false for types with a variable size, and for undefined types.
+func ElemSize(t TypeID) (n int, ok bool) {
+	n = int(elemSizes[t])
+	return n - 1, n != 0
+}
+
+// ElemSizes has the size plus one [zero for unknown].
+var elemSizes = [256]uint8{
+	M_SP_NA_1: 1 + 1,
+	M_SP_TA_1: 4 + 1,
+	M_DP_NA_1: 1 + 1,
+	M_DP_TA_1: 4 + 1,
+	M_ST_NA_1: 2 + 1,
+	M_ST_TA_1: 5 + 1,
+	M_BO_NA_1: 5 + 1,
+	M_BO_TA_1: 8 + 1,
+	M_ME_NA_1: 3 + 1,
+	M_ME_TA_1: 6 + 1,
+	M_ME_NB_1: 3 + 1,
+	M_ME_TB_1: 6 + 1,
+	M_ME_NC_1: 5 + 1,
+	M_ME_TC_1: 8 + 1,
+	M_IT_NA_1: 5 + 1,
+	M_IT_TA_1: 8 + 1,
+	M_EP_TA_1: 6 + 1,
+	M_EP_TB_1: 7 + 1,
+	M_EP_TC_1: 7 + 1,
+	M_PS_NA_1: 5 + 1,
+	M_ME_ND_1: 2 + 1,
+	M_SP_TB_1: 8 + 1,
+	M_DP_TB_1: 8 + 1,
+	M_ST_TB_1: 9 + 1,
+	M_BO_TB_1: 12 + 1,
+	M_ME_TD_1: 10 + 1,
+	M_ME_TE_1: 10 + 1,
+	M_ME_TF_1: 12 + 1,
+	M_IT_TB_1: 12 + 1,
+	M_EP_TD_1: 10 + 1,
+	M_EP_TE_1: 11 + 1,
+	M_EP_TF_1: 11 + 1,
+
+	C_SC_NA_1: 1 + 1,
+	C_DC_NA_1: 1 + 1,
+	C_RC_NA_1: 1 + 1,
+	C_SE_NA_1: 3 + 1,
+	C_SE_NB_1: 3 + 1,
+	C_SE_NC_1: 5 + 1,
+	C_BO_NA_1: 4 + 1,
+	C_SC_TA_1: 8 + 1,
+	C_DC_TA_1: 8 + 1,
+	C_RC_TA_1: 8 + 1,
+	C_SE_TA_1: 10 + 1,
+	C_SE_TB_1: 10 + 1,
+	C_SE_TC_1: 12 + 1,
+	C_BO_TA_1: 11 + 1,
+
+	M_EI_NA_1: 1 + 1,
+
+	C_IC_NA_1: 1 + 1,
+	C_CI_NA_1: 1 + 1,
+	C_RD_NA_1: 0 + 1,
+	C_CS_NA_1: 7 + 1,
+	C_TS_NA_1: 2 + 1,
+	C_RP_NA_1: 1 + 1,
+	C_CD_NA_1: 2 + 1,
+	C_TS_TA_1: 9 + 1,
+
+	P_ME_NA_1: 3 + 1,
+	P_ME_NB_1: 3 + 1,
+	P_ME_NC_1: 5 + 1,
+	P_AC_NA_1: 1 + 1,
+
+	F_FR_NA_1: 6 + 1,
+	F_SR_NA_1: 7 + 1,
+	F_SC_NA_1: 4 + 1,
+	F_LS_NA_1: 5 + 1,
+	F_AF_NA_1: 4 + 1,
+	F_DR_TA_1: 13 + 1,
+}
+
+// SniffWidths detects the address widths of a system from a stream of ASDUs,
+// with the cause of transmission in 1..2 octets (the second being the
+// originator address), the common address in 1..2 octets, and the information
+// object address in 1..3 octets. The heuristic checks each combination for a
+// consistent payload size, conform ElemSize, and for the absence of values
+// which are "not used". Types without a known size are ignored. The return is
+// false when no combination, or when multiple combinations fit all ASDUs.
+func SniffWidths(stream [][]byte) (cotSize, comSize, objSize int, ok bool) {
+	for cot := 1; cot <= 2; cot++ {
+		for com := 1; com <= 2; com++ {
+			for obj := 1; obj <= 3; obj++ {
+				if !fitsWidths(stream, cot, com, obj) {
+					continue
+				}
+				if ok {
+					return 0, 0, 0, false // ambiguous
+				}
+				cotSize, comSize, objSize, ok = cot, com, obj, true
+			}
+		}
+	}
+	return
+}
+
+// FitsWidths returns whether all ASDUs with a known ElemSize are consistent
+// with the address widths, and whether at least one such ASDU was present.
+func fitsWidths(stream [][]byte, cotSize, comSize, objSize int) bool {
+	var known bool
+	for _, asdu := range stream {
+		if len(asdu) < 2+cotSize+comSize || asdu[0] == 0 {
+			return false
+		}
+		size, ok := ElemSize(TypeID(asdu[0]))
+		if !ok {
+			continue // not applicable
+		}
+
+		enc := Enc(asdu[1])
+		if Cause(asdu[2])&63 == 0 {
+			return false
+		}
+		var comN uint
+		for i := comSize - 1; i >= 0; i-- {
+			comN = comN<<8 | uint(asdu[2+cotSize+i])
+		}
+		if comN == 0 {
+			return false
+		}
+
+		want := enc.Count() * (objSize + size)
+		if enc.AddrSeq() && enc.Count() != 0 {
+			want = objSize + enc.Count()*size
+		}
+		if len(asdu)-(2+cotSize+comSize) != want {
+			return false
+		}
+		known = true
+	}
+	return known
+}
+
 type (
 	// OrigAddr can be instantiated with OrigAddrN from System.
 	// The originator address defaults to zero.
@@ -90,10 +280,32 @@ func (_ System[Orig, Com, Obj]) NewDataUnit() DataUnit[Orig, Com, Obj] {
 	return u
 }
 
+// DecodeError provides context on a malformed ASDU. The Reason is a sentinel
+// error, such as ErrAddrSeq, which matches with errors.Is.
+type DecodeError struct {
+	Type   TypeID // type identification from the ASDU
+	Offset int    // position in the ASDU [octets]
+	Reason error  // cause of failure
+}
+
+// Error implements the builtin.error interface.
+func (e DecodeError) Error() string {
+	return fmt.Sprintf("part5: %s ASDU at offset %d: %s",
+		e.Type, e.Offset, strings.TrimPrefix(e.Reason.Error(), "part5: "))
+}
+
+// Unwrap returns the Reason for errors.Is and errors.As.
+func (e DecodeError) Unwrap() error { return e.Reason }
+
 // Adopt reads the Data Unit Identifier from the ASDU into the fields.
 // The remainder of the bytes is sliced as Info without any validation.
+// Values which are "not used" in the header are rejected with a DecodeError.
+// Info is empty when the ASDU is too short for the header, such that a reused
+// DataUnit never keeps payload from a previous ASDU.
 func (u *DataUnit[Orig, Com, Obj]) Adopt(asdu []byte) error {
-	if len(asdu) < 3+len(u.Orig)+len(u.Addr) {
+	headerSize := 3 + len(u.Orig) + len(u.Addr)
+	if len(asdu) < headerSize {
+		u.Info = u.Info[:0]
 		if len(asdu) == 0 {
 			return io.EOF
 		}
@@ -111,21 +323,27 @@ func (u *DataUnit[Orig, Com, Obj]) Adopt(asdu []byte) error {
 		u.Addr[i] = asdu[i+3+len(u.Orig)]
 	}
 
+	// slice payload; capacity limit protects asdu against appends
+	u.Info = asdu[headerSize:len(asdu):len(asdu)]
+
 	// reject values whom are "not used"
 	switch {
 	case u.Type == 0:
-		return errTypeZero
+		return DecodeError{Type: u.Type, Offset: 0, Reason: ErrTypeZero}
 	case u.Cause&63 == 0:
-		return errCauseZero
+		return DecodeError{Type: u.Type, Offset: 2, Reason: ErrCauseZero}
 	case u.Addr.N() == 0:
-		return errComAddrZero
+		return DecodeError{Type: u.Type, Offset: 3 + len(u.Orig), Reason: ErrComAddrZero}
 	}
-
-	// slice payload
-	u.Info = asdu[3+len(u.Orig)+len(u.Addr) : len(asdu) : len(asdu)]
 	return nil
 }
 
+// WireSize returns the number of octets in the ASDU encoding, i.e., the data
+// unit identifier plus the payload. See Append.
+func (u DataUnit[Orig, Com, Obj]) WireSize() int {
+	return 3 + len(u.Orig) + len(u.Addr) + len(u.Info)
+}
+
 // Append the ASDU encoding to buf and return the extended buffer.
 func (u DataUnit[Orig, Com, Obj]) Append(buf []byte) []byte {
 	buf = append(buf, byte(u.Type), byte(u.Enc), byte(u.Cause))
@@ -139,6 +357,81 @@ func (u DataUnit[Orig, Com, Obj]) Append(buf []byte) []byte {
 	return buf
 }
 
+// Errors from AppendObject.
+var (
+	errElemVar  = errors.New("part5: type identification has no fixed size for information elements")
+	errElemSize = errors.New("part5: size of information element doesn't match the type identification")
+	errObjMax   = errors.New("part5: number of information objects exceeds 127")
+	errObjNext  = errors.New("part5: address not next in sequence [VQL SQ]")
+)
+
+// AppendObject adds an information object to the payload, and it increments
+// the object count of Enc accordingly. The element must have the encoding of
+// Type, including any time tag. See ElemSize. The address is encoded only on
+// the first object when Enc has the SQ flag set. Any address after that must
+// be one higher than its previous.
+func (u *DataUnit[Orig, Com, Obj]) AppendObject(addr Obj, element []byte) error {
+	size, ok := ElemSize(u.Type)
+	if !ok {
+		return errElemVar
+	}
+	if len(element) != size {
+		return errElemSize
+	}
+	n := u.Enc.Count()
+	if n >= 127 {
+		return errObjMax
+	}
+
+	switch {
+	case !u.Enc.AddrSeq(), n == 0:
+		if u.Enc.AddrSeq() && !AllowsSequence(u.Type) {
+			return ErrAddrSeqType
+		}
+		for i := 0; i < len(addr); i++ {
+			u.Info = append(u.Info, addr[i])
+		}
+	default:
+		next, ok := u.System.ObjAddrN(Obj(u.Info[:len(addr)]).N() + uint(n))
+		if !ok {
+			return ErrAddrSeq
+		}
+		if addr != next {
+			return errObjNext
+		}
+	}
+
+	u.Info = append(u.Info, element...)
+	u.Enc++ // SQ flag unaffected
+	return nil
+}
+
+// Header is the data unit identifier without type parameters.
+type Header struct {
+	Type  TypeID
+	Enc   Enc
+	Cause Cause
+	Orig  uint // originator address
+	Addr  uint // common address
+}
+
+// Header returns the data unit identifier with numeric addresses.
+func (u DataUnit[Orig, Com, Obj]) Header() Header {
+	return Header{
+		Type:  u.Type,
+		Enc:   u.Enc,
+		Cause: u.Cause,
+		Orig:  u.Orig.N(),
+		Addr:  u.Addr.N(),
+	}
+}
+
+// IsTest returns whether the TestFlag is set on the cause of transmission. Test
+// data should be kept apart from live data, e.g., in a sandbox display.
+func (u DataUnit[Orig, Com, Obj]) IsTest() bool {
+	return u.Cause&TestFlag != 0
+}
+
 // Mirrors compares all fields for equality with the exception of Cause. For
 // Cause, only the TestFlag is compared for equality. Command responses should
 // mirror their respective requests.
@@ -1,7 +1,9 @@
 package info
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"testing"
 )
 
@@ -114,6 +116,9 @@ func TestDataUnitEncoding(t *testing.T) {
 	for _, gold := range goldenDataUnits {
 		asdu := gold.unit.Append(nil)
 		t.Logf("%s got encoded as %#x", gold.desc, asdu)
+		if n := gold.unit.WireSize(); n != len(asdu) {
+			t.Errorf("%s got wire size %d, want %d", gold.desc, n, len(asdu))
+		}
 
 		got := Wide.NewDataUnit()
 		err := got.Adopt(asdu)
@@ -133,3 +138,134 @@ func TestDataUnitEncoding(t *testing.T) {
 		}
 	}
 }
+
+// Be resillient against malicious and faulty ASDU.
+func FuzzAdopt(f *testing.F) {
+	for _, gold := range goldenDataUnits {
+		f.Add(gold.unit.Append(nil))
+	}
+
+	f.Fuzz(func(t *testing.T, asdu []byte) {
+		u := Wide.NewDataUnit()
+		u.Info = append(u.Info, "stale"...)
+		err := u.Adopt(asdu)
+		if len(u.Info) > len(asdu) {
+			t.Fatalf("got payload of %d bytes from %d bytes of ASDU", len(u.Info), len(asdu))
+		}
+		if err == nil {
+			if got := u.Append(nil); string(got) != string(asdu) {
+				t.Errorf("ASDU %#x became %#x after codec cycle", asdu, got)
+			}
+		} else if len(asdu) < 3+len(u.Orig)+len(u.Addr) && len(u.Info) != 0 {
+			t.Errorf("truncated ASDU %#x got payload %#x", asdu, u.Info)
+		}
+	})
+}
+
+// Truncated headers must not slice any payload, regardless of the widths.
+func TestAdoptTruncated(t *testing.T) {
+	asdu := []byte{byte(M_SP_NA_1), 1, byte(Spont), 1, 2, 3, 4, 5}
+
+	narrow := System[OrigAddr0, ComAddr8, ObjAddr8]{}.NewDataUnit()
+	wide := System[OrigAddr8, ComAddr16, ObjAddr24]{}.NewDataUnit()
+	for _, test := range []struct {
+		adopt      func([]byte) error
+		info       func() []byte
+		headerSize int
+	}{
+		{narrow.Adopt, func() []byte { return narrow.Info }, 4},
+		{wide.Adopt, func() []byte { return wide.Info }, 6},
+	} {
+		if err := test.adopt(asdu); err != nil {
+			t.Fatalf("header size %d got error: %s", test.headerSize, err)
+		}
+		for n := test.headerSize - 1; n >= 0; n-- {
+			err := test.adopt(asdu[:n])
+			want := io.ErrUnexpectedEOF
+			if n == 0 {
+				want = io.EOF
+			}
+			if err != want {
+				t.Errorf("header size %d truncated to %d octets got error %v, want %v", test.headerSize, n, err, want)
+			}
+			if got := test.info(); len(got) != 0 {
+				t.Errorf("header size %d truncated to %d octets got payload %#x", test.headerSize, n, got)
+			}
+		}
+	}
+}
+
+func TestDecodeError(t *testing.T) {
+	tests := []struct {
+		asdu   []byte
+		offset int
+		reason error
+	}{
+		{[]byte{0, 1, 3, 0, 1, 0}, 0, ErrTypeZero},
+		{[]byte{1, 1, 0, 0, 1, 0}, 2, ErrCauseZero},
+		{[]byte{1, 1, 3, 0, 0, 0}, 4, ErrComAddrZero},
+	}
+	for _, test := range tests {
+		u := Wide.NewDataUnit()
+		err := u.Adopt(test.asdu)
+		if !errors.Is(err, test.reason) {
+			t.Errorf("ASDU %#x got error %v, want %v", test.asdu, err, test.reason)
+			continue
+		}
+		var e DecodeError
+		if !errors.As(err, &e) {
+			t.Errorf("ASDU %#x got error type %T, want DecodeError", test.asdu, err)
+			continue
+		}
+		if e.Offset != test.offset || e.Type != TypeID(test.asdu[0]) {
+			t.Errorf("ASDU %#x got %s offset %d, want %s offset %d", test.asdu, e.Type, e.Offset, TypeID(test.asdu[0]), test.offset)
+		}
+	}
+}
+
+func TestSniffWidths(t *testing.T) {
+	var sys System[OrigAddr8, ComAddr16, ObjAddr24]
+	com := sys.MustComAddrN(1001)
+
+	newUnit := func(id TypeID, e Enc, c Cause, payload ...byte) []byte {
+		u := sys.NewDataUnit()
+		u.Type, u.Enc, u.Cause, u.Addr = id, e, c, com
+		u.Info = append(u.Info, payload...)
+		return u.Append(nil)
+	}
+	stream := [][]byte{
+		// float at address 4000
+		newUnit(M_ME_NC_1, 1, Spont, 0xa0, 0x0f, 0, 0, 0, 0x80, 0x3f, 0),
+		// single points at address 1, 2 and 3 as a sequence
+		newUnit(M_SP_NA_1, 3|0x80, Inrogen, 1, 0, 0, 0, 1, 0),
+		// station interrogation
+		newUnit(C_IC_NA_1, 1, Actcon, 0, 0, 0, 20),
+		// unknown type is ignored
+		newUnit(F_SG_NA_1, 1, File, 1, 2, 3, 4, 5),
+	}
+
+	cot, comSize, obj, ok := SniffWidths(stream)
+	if !ok || cot != 2 || comSize != 2 || obj != 3 {
+		t.Errorf("got widths %d/%d/%d, %t; want 2/2/3, true", cot, comSize, obj, ok)
+	}
+
+	// 1/2/2 fits 1/1/3 too
+	ambiguous := [][]byte{{byte(M_SP_NA_1), 1, byte(Spont), 0x01, 0x00, 0x10, 0x00, 1}}
+	if cot, com, obj, ok := SniffWidths(ambiguous); ok {
+		t.Errorf("ambiguous single point got widths %d/%d/%d, want none", cot, com, obj)
+	}
+}
+
+func TestHeader(t *testing.T) {
+	u := Wide.NewDataUnit()
+	u.Type = M_ME_NC_1
+	u.Enc = 2
+	u.Cause = Spont | TestFlag
+	u.Orig = Wide.MustOrigAddrN(7)
+	u.Addr = Wide.MustComAddrN(1001)
+
+	want := Header{Type: M_ME_NC_1, Enc: 2, Cause: Spont | TestFlag, Orig: 7, Addr: 1001}
+	if got := u.Header(); got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
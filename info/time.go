@@ -23,6 +23,12 @@ func (t2a *CP56Time2a) Set(t time.Time) {
 	t2a.setAll(t, true)
 }
 
+// SetRounded is like Set, yet it rounds to the nearest millisecond instead of
+// dropping digits. Halfway values round up.
+func (t2a *CP56Time2a) SetRounded(t time.Time) {
+	t2a.setAll(t.Round(time.Millisecond), true)
+}
+
 // SetNotAll skips both the day-of-the-week field and the summer-time flag.
 func (t2a *CP56Time2a) SetNotAll(t time.Time) {
 	t2a.setAll(t, false)
@@ -101,6 +107,12 @@ func (t2a *CP56Time2a) ClockAndMillis() (hour, min, secInMilli int) {
 		int(uint(t2a[1])<<8 | uint(t2a[0]))
 }
 
+// SummerTime returns the summer-time flag [SU]. Note that encoding with
+// SetNotAll omits the flag.
+func (t2a *CP56Time2a) SummerTime() bool {
+	return t2a[3]&0x80 != 0
+}
+
 // Reserve1 returns the RES1 bit.
 // The flag may indicate genuine time, with false for substituted time.
 //
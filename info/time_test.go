@@ -91,3 +91,25 @@ func ExampleCP24Time2a_WithinHourBefore() {
 	t := tag.WithinHourBefore(received.Add(leeway))
 	log.Println("timestamp reconstructed to", t)
 }
+
+func TestCP56Time2aSetRounded(t *testing.T) {
+	moment := time.Date(2024, 12, 31, 23, 59, 1, 999500000, time.UTC)
+
+	var truncated, rounded info.CP56Time2a
+	truncated.Set(moment)
+	rounded.SetRounded(moment)
+
+	if _, min, ms := truncated.ClockAndMillis(); min != 59 || ms != 1999 {
+		t.Errorf("Set got minute %d and %d ms, want 59 and 1999", min, ms)
+	}
+	if _, min, ms := rounded.ClockAndMillis(); min != 59 || ms != 2000 {
+		t.Errorf("SetRounded got minute %d and %d ms, want 59 and 2000", min, ms)
+	}
+
+	// carry into the next day
+	moment = time.Date(2024, 12, 31, 23, 59, 59, 999900000, time.UTC)
+	rounded.SetRounded(moment)
+	if got, want := rounded.Within20thCentury(time.UTC), time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("SetRounded got %s, want %s", got, want)
+	}
+}
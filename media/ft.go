@@ -129,8 +129,25 @@ func (ft *ft12) Encode(w io.Writer, p Packet) error {
 	return nil
 }
 
-// Decode honors the FT interface.
+// Decode honors the FT interface. Any faults from a FaultReader, during the
+// read of a packet, take precedence over ErrCheck and ErrDataFit.
 func (ft *ft12) Decode(r io.Reader) (Packet, error) {
+	fr, ok := r.(FaultReader)
+	if !ok {
+		return ft.decode(r)
+	}
+
+	fr.LineFault() // clear
+	p, err := ft.decode(r)
+	if err == nil || err == ErrCheck || err == ErrDataFit {
+		if f := fr.LineFault(); f != 0 {
+			return Packet{}, f
+		}
+	}
+	return p, err
+}
+
+func (ft *ft12) decode(r io.Reader) (Packet, error) {
 	buf := &ft.buf
 	var size int // octet count of user data
 
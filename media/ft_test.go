@@ -21,6 +21,91 @@ var GoldenFT12s = []GoldenFT{
 	// Examples borrowed from Beckhoff Information System:
 	{NewFT12(2, 20, Ack, Nack), "100b0c001716", 12, OK, ""},
 	{NewFT12(2, 20, Ack, Nack), "680b0b68080c0065010a0c000000059516", 12, Data, "65010a0c00000005"},
+
+	// fixed length per address size
+	{NewFT12(1, 20, Ack, Nack), "1049075016", 7, FromPrimaryFlag | StatusReq, ""},
+	{NewFT12(2, 20, Ack, Nack), "105b3412a116", 0x1234, FromPrimaryFlag | FrameCountValidFlag | Class2Req, ""},
+	// variable length per address size, with checksum overflow
+	{NewFT12(1, 20, Ack, Nack), "680909687307640106070000140016", 7, FromPrimaryFlag | FrameCountFlag | FrameCountValidFlag | Give, "64010607000014"},
+	{NewFT12(2, 20, Ack, Nack), "68060668083412ffffff4b16", 0x1234, Data, "ffffff"},
+	// data size at the limit
+	{NewFT12(1, 3, Ack, Nack), "680505680807aabbcc4016", 7, Data, "aabbcc"},
+}
+
+func TestFT12SingleChars(t *testing.T) {
+	ft := NewFT12(1, 20, Ack, Nack|XSDemandFlag)
+	r := bytes.NewReader([]byte{0xe5, 0xa2})
+	for _, want := range []Ctrl{Ack, Nack | XSDemandFlag} {
+		got, err := ft.Decode(r)
+		if err != nil {
+			t.Fatal("decode error:", err)
+		}
+		if got.Addr != GlobalAddr || got.Ctrl != want || got.Data != nil {
+			t.Errorf("got %+v, want control field %#x with the global address", got, want)
+		}
+	}
+}
+
+func TestFT12DataFit(t *testing.T) {
+	// one octet beyond the limit on decode
+	ft := NewFT12(1, 3, Ack, Nack)
+	if _, err := ft.Decode(bytes.NewReader([]byte{0x68, 6, 6, 0x68, 0x08, 0x07, 1, 2, 3, 4, 0x19, 0x16})); err != ErrDataFit {
+		t.Errorf("decode of 4 octets got error %v, want %v", err, ErrDataFit)
+	}
+
+	// packet capacity of 255 octets includes control field and address
+	ft = NewFT12(2, 252, Ack, Nack)
+	if err := ft.Encode(io.Discard, Packet{7, Data, make([]byte, 252)}); err != nil {
+		t.Errorf("encode of 252 octets got error: %s", err)
+	}
+	if err := ft.Encode(io.Discard, Packet{7, Data, make([]byte, 253)}); err != ErrDataFit {
+		t.Errorf("encode of 253 octets got error %v, want %v", err, ErrDataFit)
+	}
+}
+
+// Loopback passes each packet through an io.Pipe, from an encoder to a decoder
+// of the same format class.
+func loopback(t *testing.T, newFT func() FT, packets []Packet) {
+	t.Helper()
+	r, w := io.Pipe()
+	go func() {
+		enc := newFT()
+		for _, p := range packets {
+			if err := enc.Encode(w, p); err != nil {
+				w.CloseWithError(err)
+				return
+			}
+		}
+		w.Close()
+	}()
+
+	dec := newFT()
+	for i, want := range packets {
+		got, err := dec.Decode(r)
+		if err != nil {
+			t.Fatalf("packet %d: decode error: %s", i, err)
+		}
+		if got.Addr != want.Addr || got.Ctrl != want.Ctrl || !bytes.Equal(got.Data, want.Data) {
+			t.Errorf("packet %d: got %+v, want %+v", i, got, want)
+		}
+	}
+	if _, err := dec.Decode(r); err != io.EOF {
+		t.Errorf("got error %v after the last packet, want EOF", err)
+	}
+}
+
+func TestFT12Loopback(t *testing.T) {
+	for addrSize := 1; addrSize <= 2; addrSize++ {
+		addr := Addr(1)
+		packets := []Packet{
+			{addr, FromPrimaryFlag | Reset, nil},
+			{addr, OK, nil},
+			{addr, FromPrimaryFlag | FrameCountValidFlag | Give, []byte{0x64, 0x01, 0x06, 0x00, 0x01, 0x00, 0x00, 0x14}},
+			{addr, Ack, nil},
+			{addr, Data, bytes.Repeat([]byte{0xff}, 252)},
+		}
+		loopback(t, func() FT { return NewFT12(addrSize, 252, Ack, Nack) }, packets)
+	}
 }
 
 func TestGoldenFT12Encodes(t *testing.T) {
@@ -114,3 +199,61 @@ func TestGoldenFT12EOF(t *testing.T) {
 		}
 	}
 }
+
+// FaultyReader corrupts the character at offset with a fault.
+type faultyReader struct {
+	feed   []byte
+	offset int
+	fault  LineFault
+	faults LineFault
+}
+
+func (r *faultyReader) Read(p []byte) (n int, err error) {
+	if len(r.feed) == 0 {
+		return 0, io.EOF
+	}
+	p[0] = r.feed[0]
+	if r.offset == 0 {
+		p[0] ^= 0x01 // flip one bit
+		r.faults |= r.fault
+	}
+	r.feed = r.feed[1:]
+	r.offset--
+	return 1, nil
+}
+
+func (r *faultyReader) LineFault() LineFault {
+	f := r.faults
+	r.faults = 0
+	return f
+}
+
+func TestFT12LineFault(t *testing.T) {
+	for _, gold := range GoldenFT12s {
+		feed, err := hex.DecodeString(gold.Feed)
+		if err != nil {
+			t.Fatalf("%s: broken test feed: %s", gold.Feed, err)
+		}
+
+		for i := range feed {
+			r := &faultyReader{feed: feed, offset: i, fault: ParityFault}
+			got, err := gold.Impl.Decode(r)
+			if err != ParityFault {
+				t.Errorf("%s: parity error at offset %d got error %v, want %v", gold.Feed, i, err, ParityFault)
+			}
+			if got.Data != nil {
+				t.Errorf("%s: parity error at offset %d got data %#x", gold.Feed, i, got.Data)
+			}
+		}
+
+		// faults before the packet are not accounted for
+		r := &faultyReader{feed: feed, offset: -1, faults: OverrunFault}
+		if _, err := gold.Impl.Decode(r); err != nil {
+			t.Errorf("%s: got error %v after line fault from earlier", gold.Feed, err)
+		}
+	}
+
+	if got, want := (ParityFault | OverrunFault).Error(), "part5: serial line parity and overrun error"; got != want {
+		t.Errorf("got error message %q, want %q", got, want)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"io"
 	"strconv"
+	"strings"
 )
 
 // Fixed serial configuration parameters defined in section 1.
@@ -18,6 +19,47 @@ const (
 // FrameBits is the number of bits per character needed at the physical layer.
 const FrameBits = 1 + DataSize + 1 + StopBits
 
+// LineFault is a character error at the physical layer, as detected by the UART.
+// Multiple faults can be combined with a bitwise OR.
+type LineFault uint8
+
+// UART errors
+const (
+	ParityFault  LineFault = 1 << iota // check bit mismatch
+	FramingFault                       // stop bit missing
+	OverrunFault                       // character lost due to buffer overflow
+)
+
+// Error implements the builtin.error interface.
+func (f LineFault) Error() string {
+	var names []string
+	if f&ParityFault != 0 {
+		names = append(names, "parity")
+	}
+	if f&FramingFault != 0 {
+		names = append(names, "framing")
+	}
+	if f&OverrunFault != 0 {
+		names = append(names, "overrun")
+	}
+	if len(names) == 0 {
+		return "part5: no line fault"
+	}
+	return "part5: serial line " + strings.Join(names, " and ") + " error"
+}
+
+// FaultReader is an io.Reader for a serial line which keeps track of faults at
+// the physical layer. Serial drivers commonly pass corrupted characters as is,
+// such that a packet fails on ErrCheck without context. Decode of an FT checks
+// for any line faults instead.
+type FaultReader interface {
+	io.Reader
+
+	// LineFault returns the faults since the previous call, if any, with
+	// zero for none.
+	LineFault() LineFault
+}
+
 // FT is a format class for Packet encoding.
 type FT interface {
 	Encode(io.Writer, Packet) error
@@ -473,6 +473,28 @@ type InitEndMonitor[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr] inte
 	InitEnd(info.DataUnit[Orig, Com, Obj], info.InitCause)
 }
 
+// PrivateMonitor consumes type identifiers from the private range 128..255,
+// i.e., “for special use” conform table 8 of companion standard 101. The
+// Monitor interface does not include PrivateMonitor. MonitorDataUnit checks
+// for the interface on each private type identifier instead. See NewPrivate.
+// The wrappers in this package, such as ObjAddrMux, pass private types on.
+type PrivateMonitor[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr] interface {
+	// Private gets called with the payload as is. The return is
+	// ErrNotMonitor when no PrivateMonitor accepts the type identifier,
+	// e.g., when a wrapper passes to a Monitor without Private.
+	Private(info.DataUnit[Orig, Com, Obj]) error
+}
+
+// MonitorPrivate passes u to mon when it implements PrivateMonitor, and it
+// returns ErrNotMonitor otherwise.
+func monitorPrivate[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr](mon Monitor[Orig, Com, Obj], u info.DataUnit[Orig, Com, Obj]) error {
+	p, ok := mon.(PrivateMonitor[Orig, Com, Obj])
+	if !ok {
+		return ErrNotMonitor
+	}
+	return p.Private(u)
+}
+
 // MonitorDataUnit has two errors for the selection on type identifier.
 var (
 	// ErrNotMonitor rejects an info.DataUnit based on its type identifier.
@@ -484,14 +506,36 @@ var (
 
 var errInfoSize = errors.New("part5: size of ASDU payload doesn't match the variable structure qualifier")
 
+// payloadErr returns an info.DecodeError with the offset of the payload.
+func payloadErr[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr](u *info.DataUnit[Orig, Com, Obj], reason error) error {
+	return info.DecodeError{
+		Type:   u.Type,
+		Offset: 3 + len(u.Orig) + len(u.Addr),
+		Reason: reason,
+	}
+}
+
 // MonitorDataUnit propagates information objects in u to the corresponding
 // listener method from mon, filtering with ErrNotMontior and ErrMonitorReserve.
 // DataUnits with no [zero] information elements pass without invocation to mon.
+// Private type identifiers pass to mon only when it implements PrivateMonitor.
 func MonitorDataUnit[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr](mon Monitor[Orig, Com, Obj], u info.DataUnit[Orig, Com, Obj]) error {
-	// monitor type identifiers (M_*) are in range 1..44
-	if u.Type-1 > 43 {
+	if u.Type >= 128 {
+		return monitorPrivate(mon, u)
+	}
+
+	// monitor type identifiers (M_*) are in range 1..44,
+	// plus system information M_EI_NA_1
+	if u.Type-1 > 43 && u.Type != info.M_EI_NA_1 {
 		return ErrNotMonitor
 	}
+	if u.Type > info.M_ME_ND_1 && u.Type < info.M_SP_TB_1 || u.Type > info.M_EP_TF_1 && u.Type != info.M_EI_NA_1 {
+		return ErrMonitorReserve
+	}
+	if u.Enc.AddrSeq() && !info.AllowsSequence(u.Type) {
+		// variable structure qualifier at offset 1
+		return info.DecodeError{Type: u.Type, Offset: 1, Reason: info.ErrAddrSeqType}
+	}
 
 	// NOTE: Go can't get the array length from a generic as a constant yet.
 	var addr Obj
@@ -509,7 +553,7 @@ func MonitorDataUnit[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr](mon
 			}
 		} else {
 			if len(u.Info) != u.Enc.Count()*(len(addr)+1) {
-				return errInfoSize
+				return payloadErr(&u, errInfoSize)
 			}
 			for i := 0; i+len(addr)+1 <= len(u.Info); i += len(addr) + 1 {
 				mon.SinglePt(u,
@@ -520,11 +564,8 @@ func MonitorDataUnit[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr](mon
 		}
 
 	case info.M_SP_TA_1: // single-point with 3 octet time-tag
-		if u.Enc.AddrSeq() {
-			return errors.New("part5: ASDU address sequence with M_SP_TA_1 not allowed")
-		}
 		if len(u.Info) != u.Enc.Count()*(len(addr)+4) {
-			return errInfoSize
+			return payloadErr(&u, errInfoSize)
 		}
 		for i := 0; i+len(addr)+4 <= len(u.Info); i += len(addr) + 4 {
 			mon.SinglePtAtMinute(u,
@@ -535,11 +576,8 @@ func MonitorDataUnit[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr](mon
 		}
 
 	case info.M_SP_TB_1: // single-point with 7 octet time-tag
-		if u.Enc.AddrSeq() {
-			return errors.New("part5: ASDU address sequence with M_SP_TB_1 not allowed")
-		}
 		if len(u.Info) != u.Enc.Count()*(len(addr)+8) {
-			return errInfoSize
+			return payloadErr(&u, errInfoSize)
 		}
 		for i := 0; i+len(addr)+8 <= len(u.Info); i += len(addr) + 8 {
 			mon.SinglePtAtMoment(u,
@@ -568,7 +606,7 @@ func MonitorDataUnit[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr](mon
 			}
 		} else {
 			if len(u.Info) != u.Enc.Count()*(len(addr)+5) {
-				return errInfoSize
+				return payloadErr(&u, errInfoSize)
 			}
 			for i := 0; i+len(addr)+5 <= len(u.Info); i += len(addr) + 5 {
 				mon.SinglePtChangePack(u,
@@ -595,7 +633,7 @@ func MonitorDataUnit[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr](mon
 			}
 		} else {
 			if len(u.Info) != u.Enc.Count()*(len(addr)+1) {
-				return errInfoSize
+				return payloadErr(&u, errInfoSize)
 			}
 			for i := 0; i+len(addr)+1 <= len(u.Info); i += len(addr) + 1 {
 				mon.DoublePt(u,
@@ -606,11 +644,8 @@ func MonitorDataUnit[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr](mon
 		}
 
 	case info.M_DP_TA_1: // double-point with 3 octet time-tag
-		if u.Enc.AddrSeq() {
-			return errors.New("part5: ASDU address sequence with M_DP_TA_1 not allowed")
-		}
 		if len(u.Info) != u.Enc.Count()*(len(addr)+4) {
-			return errInfoSize
+			return payloadErr(&u, errInfoSize)
 		}
 		for i := 0; i+len(addr)+4 <= len(u.Info); i += len(addr) + 4 {
 			mon.DoublePtAtMinute(u,
@@ -621,11 +656,8 @@ func MonitorDataUnit[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr](mon
 		}
 
 	case info.M_DP_TB_1: // double-point with 7 octet time-tag
-		if u.Enc.AddrSeq() {
-			return errors.New("part5: ASDU address sequence with M_DP_TB_1 not allowed")
-		}
 		if len(u.Info) != u.Enc.Count()*(len(addr)+8) {
-			return errInfoSize
+			return payloadErr(&u, errInfoSize)
 		}
 		for i := 0; i+len(addr)+8 <= len(u.Info); i += len(addr) + 8 {
 			mon.DoublePtAtMoment(u,
@@ -647,7 +679,7 @@ func MonitorDataUnit[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr](mon
 			}
 		} else {
 			if len(u.Info) != u.Enc.Count()*(len(addr)+2) {
-				return errInfoSize
+				return payloadErr(&u, errInfoSize)
 			}
 			for i := 0; i+len(addr)+2 <= len(u.Info); i += len(addr) + 2 {
 				mon.Step(u,
@@ -658,11 +690,8 @@ func MonitorDataUnit[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr](mon
 		}
 
 	case info.M_ST_TA_1: // step position with 3 octet time-tag
-		if u.Enc.AddrSeq() {
-			return errors.New("part5: ASDU address sequence with M_ST_TA_1 not allowed")
-		}
 		if len(u.Info) != u.Enc.Count()*(len(addr)+5) {
-			return errInfoSize
+			return payloadErr(&u, errInfoSize)
 		}
 		for i := 0; i+len(addr)+5 <= len(u.Info); i += len(addr) + 5 {
 			mon.StepAtMinute(u,
@@ -673,11 +702,8 @@ func MonitorDataUnit[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr](mon
 		}
 
 	case info.M_ST_TB_1: // step position with 7 octet time-tag
-		if u.Enc.AddrSeq() {
-			return errors.New("part5: ASDU address sequence with M_ST_TB_1 not allowed")
-		}
 		if len(u.Info) != u.Enc.Count()*(len(addr)+9) {
-			return errInfoSize
+			return payloadErr(&u, errInfoSize)
 		}
 		for i := 0; i+len(addr)+9 <= len(u.Info); i += len(addr) + 9 {
 			mon.StepAtMoment(u,
@@ -699,7 +725,7 @@ func MonitorDataUnit[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr](mon
 			}
 		} else {
 			if len(u.Info) != u.Enc.Count()*(len(addr)+5) {
-				return errInfoSize
+				return payloadErr(&u, errInfoSize)
 			}
 			for i := 0; i+len(addr)+5 <= len(u.Info); i += len(addr) + 5 {
 				mon.Bits(u,
@@ -710,11 +736,8 @@ func MonitorDataUnit[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr](mon
 		}
 
 	case info.M_BO_TA_1: // bit string with 3 octet time-tag
-		if u.Enc.AddrSeq() {
-			return errors.New("part5: ASDU address sequence with M_BO_TA_1 not allowed")
-		}
 		if len(u.Info) != u.Enc.Count()*(len(addr)+8) {
-			return errInfoSize
+			return payloadErr(&u, errInfoSize)
 		}
 		for i := 0; i+len(addr)+8 <= len(u.Info); i += len(addr) + 8 {
 			mon.BitsAtMinute(u,
@@ -725,11 +748,8 @@ func MonitorDataUnit[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr](mon
 		}
 
 	case info.M_BO_TB_1: // bit string with 7 octet time-tag
-		if u.Enc.AddrSeq() {
-			return errors.New("part5: ASDU address sequence with M_BO_TB_1 not allowed")
-		}
 		if len(u.Info) != u.Enc.Count()*(len(addr)+12) {
-			return errInfoSize
+			return payloadErr(&u, errInfoSize)
 		}
 		for i := 0; i+len(addr)+12 <= len(u.Info); i += len(addr) + 12 {
 			mon.BitsAtMoment(u,
@@ -751,7 +771,7 @@ func MonitorDataUnit[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr](mon
 			}
 		} else {
 			if len(u.Info) != u.Enc.Count()*(len(addr)+2) {
-				return errInfoSize
+				return payloadErr(&u, errInfoSize)
 			}
 			for i := 0; i+len(addr)+2 <= len(u.Info); i += len(addr) + 2 {
 				mon.NormUnqual(u,
@@ -773,7 +793,7 @@ func MonitorDataUnit[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr](mon
 			}
 		} else {
 			if len(u.Info) != u.Enc.Count()*(len(addr)+3) {
-				return errInfoSize
+				return payloadErr(&u, errInfoSize)
 			}
 			for i := 0; i+len(addr)+3 <= len(u.Info); i += len(addr) + 3 {
 				mon.Norm(u,
@@ -784,11 +804,8 @@ func MonitorDataUnit[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr](mon
 		}
 
 	case info.M_ME_TA_1: // normalized value with 3 octet time-tag
-		if u.Enc.AddrSeq() {
-			return errors.New("part5: ASDU address sequence with M_ME_TA_1 not allowed")
-		}
 		if len(u.Info) != u.Enc.Count()*(len(addr)+6) {
-			return errInfoSize
+			return payloadErr(&u, errInfoSize)
 		}
 		for i := 0; i+len(addr)+6 <= len(u.Info); i += len(addr) + 6 {
 			mon.NormAtMinute(u,
@@ -799,11 +816,8 @@ func MonitorDataUnit[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr](mon
 		}
 
 	case info.M_ME_TD_1: // normalized value with 7 octet time-tag
-		if u.Enc.AddrSeq() {
-			return errors.New("part5: ASDU address sequence with M_ME_TD_1 not allowed")
-		}
 		if len(u.Info) != u.Enc.Count()*(len(addr)+10) {
-			return errInfoSize
+			return payloadErr(&u, errInfoSize)
 		}
 		for i := 0; i+len(addr)+10 <= len(u.Info); i += len(addr) + 10 {
 			mon.NormAtMoment(u,
@@ -832,7 +846,7 @@ func MonitorDataUnit[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr](mon
 			}
 		} else {
 			if len(u.Info) != u.Enc.Count()*(len(addr)+3) {
-				return errInfoSize
+				return payloadErr(&u, errInfoSize)
 			}
 			for i := 0; i+len(addr)+3 <= len(u.Info); i += len(addr) + 3 {
 				mon.Scaled(u,
@@ -848,11 +862,8 @@ func MonitorDataUnit[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr](mon
 		}
 
 	case info.M_ME_TB_1: // scaled value with 3 octet time-tag
-		if u.Enc.AddrSeq() {
-			return errors.New("part5: ASDU address sequence with M_ME_TB_1 not allowed")
-		}
 		if len(u.Info) != u.Enc.Count()*(len(addr)+6) {
-			return errInfoSize
+			return payloadErr(&u, errInfoSize)
 		}
 		for i := 0; i+len(addr)+6 <= len(u.Info); i += len(addr) + 6 {
 			mon.ScaledAtMinute(u,
@@ -868,11 +879,8 @@ func MonitorDataUnit[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr](mon
 		}
 
 	case info.M_ME_TE_1: // scaled value with 7 octet time-tag
-		if u.Enc.AddrSeq() {
-			return errors.New("part5: ASDU address sequence with M_ME_TE_1 not allowed")
-		}
 		if len(u.Info) != u.Enc.Count()*(len(addr)+10) {
-			return errInfoSize
+			return payloadErr(&u, errInfoSize)
 		}
 		for i := 0; i+len(addr)+10 <= len(u.Info); i += len(addr) + 10 {
 			mon.ScaledAtMoment(u,
@@ -907,7 +915,7 @@ func MonitorDataUnit[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr](mon
 			}
 		} else {
 			if len(u.Info) != u.Enc.Count()*(len(addr)+5) {
-				return errInfoSize
+				return payloadErr(&u, errInfoSize)
 			}
 			for i := 0; i+len(addr)+5 <= len(u.Info); i += len(addr) + 5 {
 				mon.Float(u,
@@ -923,11 +931,8 @@ func MonitorDataUnit[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr](mon
 		}
 
 	case info.M_ME_TC_1: // floating point with 3 octet time-tag
-		if u.Enc.AddrSeq() {
-			return errors.New("part5: ASDU address sequence with M_ME_TC_1 not allowed")
-		}
 		if len(u.Info) != u.Enc.Count()*(len(addr)+8) {
-			return errInfoSize
+			return payloadErr(&u, errInfoSize)
 		}
 		for i := 0; i+len(addr)+8 <= len(u.Info); i += len(addr) + 8 {
 			mon.FloatAtMinute(u,
@@ -943,11 +948,8 @@ func MonitorDataUnit[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr](mon
 		}
 
 	case info.M_ME_TF_1: // floating point with 7 octet time-tag
-		if u.Enc.AddrSeq() {
-			return errors.New("part5: ASDU address sequence with M_ME_TF_1 not allowed")
-		}
 		if len(u.Info) != u.Enc.Count()*(len(addr)+12) {
-			return errInfoSize
+			return payloadErr(&u, errInfoSize)
 		}
 		for i := 0; i+len(addr)+12 <= len(u.Info); i += len(addr) + 12 {
 			mon.FloatAtMoment(u,
@@ -974,7 +976,7 @@ func MonitorDataUnit[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr](mon
 			}
 		} else {
 			if len(u.Info) != u.Enc.Count()*(len(addr)+5) {
-				return errInfoSize
+				return payloadErr(&u, errInfoSize)
 			}
 			for i := 0; i+len(addr)+5 <= len(u.Info); i += len(addr) + 5 {
 				mon.Totals(u, Obj(u.Info[i:i+len(addr)]),
@@ -984,11 +986,8 @@ func MonitorDataUnit[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr](mon
 		}
 
 	case info.M_IT_TA_1: // integrated totals with 3 octet time-tag.
-		if u.Enc.AddrSeq() {
-			return errors.New("part5: ASDU address sequence with M_IT_TA_1 not allowed")
-		}
 		if len(u.Info) != u.Enc.Count()*(len(addr)+8) {
-			return errInfoSize
+			return payloadErr(&u, errInfoSize)
 		}
 		for i := 0; i+len(addr)+8 <= len(u.Info); i += len(addr) + 8 {
 			mon.TotalsAtMinute(u,
@@ -999,11 +998,8 @@ func MonitorDataUnit[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr](mon
 		}
 
 	case info.M_IT_TB_1: // integrated totals with 7 octet time-tag.
-		if u.Enc.AddrSeq() {
-			return errors.New("part5: ASDU address sequence with M_IT_TB_1 not allowed")
-		}
 		if len(u.Info) != u.Enc.Count()*(len(addr)+12) {
-			return errInfoSize
+			return payloadErr(&u, errInfoSize)
 		}
 		for i := 0; i+len(addr)+12 <= len(u.Info); i += len(addr) + 12 {
 			mon.TotalsAtMoment(u,
@@ -1014,35 +1010,27 @@ func MonitorDataUnit[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr](mon
 		}
 
 	case info.M_EP_TA_1: // protection equipment event with 3-octet time tag
-		if u.Enc.AddrSeq() {
-			return errors.New("part5: ASDU address sequence with M_EP_TA_1 not allowed")
-		} else {
-			if len(u.Info) != u.Enc.Count()*(len(addr)+6) {
-				return errInfoSize
-			}
-			for i := 0; i+len(addr)+6 <= len(u.Info); i += len(addr) + 6 {
-				mon.ProtectAtMinute(u,
-					Obj(u.Info[i:i+len(addr)]),
-					info.ProtectEvent(u.Info[i+len(addr):i+len(addr)+3]),
-					info.CP24Time2a(u.Info[i+len(addr)+3:i+len(addr)+6]),
-				)
-			}
+		if len(u.Info) != u.Enc.Count()*(len(addr)+6) {
+			return payloadErr(&u, errInfoSize)
+		}
+		for i := 0; i+len(addr)+6 <= len(u.Info); i += len(addr) + 6 {
+			mon.ProtectAtMinute(u,
+				Obj(u.Info[i:i+len(addr)]),
+				info.ProtectEvent(u.Info[i+len(addr):i+len(addr)+3]),
+				info.CP24Time2a(u.Info[i+len(addr)+3:i+len(addr)+6]),
+			)
 		}
 
 	case info.M_EP_TD_1: // protection equipment event with 7-octet time tag
-		if u.Enc.AddrSeq() {
-			return errors.New("part5: ASDU address sequence with M_EP_TD_1 not allowed")
-		} else {
-			if len(u.Info) != u.Enc.Count()*(len(addr)+10) {
-				return errInfoSize
-			}
-			for i := 0; i+len(addr)+10 <= len(u.Info); i += len(addr) + 10 {
-				mon.ProtectAtMoment(u,
-					Obj(u.Info[i:i+len(addr)]),
-					info.ProtectEvent(u.Info[i+len(addr):i+len(addr)+3]),
-					info.CP56Time2a(u.Info[i+len(addr)+3:i+len(addr)+10]),
-				)
-			}
+		if len(u.Info) != u.Enc.Count()*(len(addr)+10) {
+			return payloadErr(&u, errInfoSize)
+		}
+		for i := 0; i+len(addr)+10 <= len(u.Info); i += len(addr) + 10 {
+			mon.ProtectAtMoment(u,
+				Obj(u.Info[i:i+len(addr)]),
+				info.ProtectEvent(u.Info[i+len(addr):i+len(addr)+3]),
+				info.CP56Time2a(u.Info[i+len(addr)+3:i+len(addr)+10]),
+			)
 		}
 
 	case info.M_EP_TB_1: // start of protection equipment with 3-octet time tag
@@ -1050,7 +1038,7 @@ func MonitorDataUnit[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr](mon
 			return errors.New("part5: variable structure qualifier of M_EP_TB_1 not 1")
 		}
 		if len(u.Info) != len(addr)+7 {
-			return errInfoSize
+			return payloadErr(&u, errInfoSize)
 		}
 		mon.ProtectStartAtMinute(u,
 			Obj(u.Info[:len(addr)]),
@@ -1063,7 +1051,7 @@ func MonitorDataUnit[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr](mon
 			return errors.New("part5: variable structure qualifier of M_EP_TE_1 not 1")
 		}
 		if len(u.Info) != len(addr)+11 {
-			return errInfoSize
+			return payloadErr(&u, errInfoSize)
 		}
 		mon.ProtectStartAtMoment(u,
 			Obj(u.Info[:len(addr)]),
@@ -1076,7 +1064,7 @@ func MonitorDataUnit[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr](mon
 			return errors.New("part5: variable structure qualifier of M_EP_TC_1 not 1")
 		}
 		if len(u.Info) != len(addr)+7 {
-			return errInfoSize
+			return payloadErr(&u, errInfoSize)
 		}
 		mon.ProtectOutAtMinute(u,
 			Obj(u.Info[:len(addr)]),
@@ -1089,7 +1077,7 @@ func MonitorDataUnit[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr](mon
 			return errors.New("part5: variable structure qualifier of M_EP_TF_1 not 1")
 		}
 		if len(u.Info) != len(addr)+11 {
-			return errInfoSize
+			return payloadErr(&u, errInfoSize)
 		}
 		mon.ProtectOutAtMoment(u,
 			Obj(u.Info[:len(addr)]),
@@ -1099,7 +1087,7 @@ func MonitorDataUnit[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr](mon
 
 	case info.M_EI_NA_1: // end of initialization
 		if len(u.Info) != len(addr)+1 {
-			return errInfoSize
+			return payloadErr(&u, errInfoSize)
 		}
 		mon.InitEnd(u, info.InitCause(u.Info[len(addr)]))
 
@@ -1111,14 +1099,64 @@ func MonitorDataUnit[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr](mon
 
 func addrSeqStart[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr](u *info.DataUnit[Orig, Com, Obj], encSize int) (addr Obj, err error) {
 	if len(u.Info) != len(addr)+u.Enc.Count()*encSize {
-		return addr, errInfoSize
+		return addr, payloadErr(u, errInfoSize)
 	}
 	addr = Obj(u.Info[:len(addr)])
 
 	// overflow check
 	lastN := addr.N() + uint(u.Enc.Count()) - 1
 	if _, ok := u.System.ObjAddrN(lastN); !ok {
-		return addr, info.ErrAddrSeq
+		return addr, payloadErr(u, info.ErrAddrSeq)
 	}
 	return addr, nil
 }
+
+// ErrNotTimed rejects an info.DataUnit based on its type identifier.
+var ErrNotTimed = errors.New("part5: ASDU type identifier has no time tag")
+
+// EachTimed calls f for each information object in u, in order of appearance,
+// with the time tag reconstructed. Raw has the information element(s) without
+// the address and without the time tag. Types without a time tag are rejected
+// with ErrNotTimed. The time-zone and the leeway apply the same way as they do
+// for the proxies, e.g., SinglePtProxy. Time is zero for Invalid() tags.
+func EachTimed[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr](u info.DataUnit[Orig, Com, Obj], zone *time.Location, leeway time.Duration, f func(addr Obj, raw []byte, t time.Time)) error {
+	var tagSize int
+	switch u.Type {
+	case info.M_SP_TA_1, info.M_DP_TA_1, info.M_ST_TA_1, info.M_BO_TA_1,
+		info.M_ME_TA_1, info.M_ME_TB_1, info.M_ME_TC_1, info.M_IT_TA_1,
+		info.M_EP_TA_1, info.M_EP_TB_1, info.M_EP_TC_1:
+		tagSize = 3
+	case info.M_SP_TB_1, info.M_DP_TB_1, info.M_ST_TB_1, info.M_BO_TB_1,
+		info.M_ME_TD_1, info.M_ME_TE_1, info.M_ME_TF_1, info.M_IT_TB_1,
+		info.M_EP_TD_1, info.M_EP_TE_1, info.M_EP_TF_1:
+		tagSize = 7
+	default:
+		return ErrNotTimed
+	}
+	if u.Enc.AddrSeq() {
+		// variable structure qualifier at offset 1
+		return info.DecodeError{Type: u.Type, Offset: 1, Reason: info.ErrAddrSeqType}
+	}
+
+	// NOTE: Go can't get the array length from a generic as a constant yet.
+	var addr Obj
+	elemSize, _ := info.ElemSize(u.Type)
+	objSize := len(addr) + elemSize
+	if len(u.Info) != u.Enc.Count()*objSize {
+		return payloadErr(&u, errInfoSize)
+	}
+
+	for i := 0; i+objSize <= len(u.Info); i += objSize {
+		raw := u.Info[i+len(addr) : i+objSize-tagSize]
+		var t time.Time
+		if tagSize == 3 {
+			tag := info.CP24Time2a(u.Info[i+objSize-3 : i+objSize])
+			t = tag.WithinHourBefore(time.Now().In(zone).Add(leeway))
+		} else {
+			tag := info.CP56Time2a(u.Info[i+objSize-7 : i+objSize])
+			t = tag.Within20thCentury(zone)
+		}
+		f(Obj(u.Info[i:i+len(addr)]), raw, t)
+	}
+	return nil
+}
@@ -2,15 +2,45 @@ package part5
 
 import (
 	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/pascaldekloe/part5/info"
 )
 
+// TestExchange returns the setup of most tests, on common address 7.
+func testExchange() Exchange[info.OrigAddr0, info.ComAddr8, info.ObjAddr16] {
+	var sys info.System[info.OrigAddr0, info.ComAddr8, info.ObjAddr16]
+	return Exchange[info.OrigAddr0, info.ComAddr8, info.ObjAddr16]{
+		ComAddr: sys.MustComAddrN(7),
+	}
+}
+
 // Be resillient against malicious and faulty ASDU.
 func FuzzMonitorWideASDU(f *testing.F) {
 	var sys info.System[info.OrigAddr8, info.ComAddr16, info.ObjAddr24]
 
+	// seed corpus from the builders
+	r := Exchange[info.OrigAddr8, info.ComAddr16, info.ObjAddr24]{
+		OrigAddr: sys.MustOrigAddrN(9),
+		ComAddr:  sys.MustComAddrN(1001),
+	}.Report()
+	seed := func(u info.DataUnit[info.OrigAddr8, info.ComAddr16, info.ObjAddr24], err error) {
+		if err != nil {
+			f.Fatal("seed build error:", err)
+		}
+		f.Add(u.Append(nil))
+	}
+	var tag info.CP56Time2a
+	tag.Set(time.Date(2024, 2, 29, 13, 14, 15, 16e6, time.UTC))
+	seed(r.Object(info.M_SP_TB_1, info.Spont, sys.MustObjAddrN(1), append([]byte{1}, tag[:]...)))
+	seed(r.Seq(info.M_ME_NC_1, info.Inrogen, sys.MustObjAddrN(2), []byte{0, 0, 0x80, 0x3f, 0}, []byte{0, 0, 0, 0, 0x80}))
+	seed(r.Object(info.M_IT_NA_1, info.Reqcogen, sys.MustObjAddrN(3), []byte{1, 2, 3, 4, 5}))
+	seed(r.Object(info.M_EI_NA_1, info.Init, sys.MustObjAddrN(0), []byte{0}))
+
 	f.Fuzz(func(t *testing.T, asdu []byte) {
 		var buf bytes.Buffer
 		mon := NewMonitorDelegateDefault(NewLogger(sys, &buf))
@@ -24,3 +54,321 @@ func FuzzMonitorWideASDU(f *testing.F) {
 		}
 	})
 }
+
+func TestReportSeq(t *testing.T) {
+	var sys info.System[info.OrigAddr0, info.ComAddr8, info.ObjAddr16]
+	r := testExchange().Report()
+
+	_, err := r.Seq(info.M_SP_TA_1, info.Spont, sys.MustObjAddrN(100), []byte{1, 0, 0, 0})
+	if !errors.Is(err, info.ErrAddrSeqType) {
+		t.Errorf("M_SP_TA_1 sequence got error %v, want %v", err, info.ErrAddrSeqType)
+	}
+	_, err = r.Seq(info.M_SP_NA_1, info.Spont, sys.MustObjAddrN(0xffff), []byte{0}, []byte{1})
+	if !errors.Is(err, info.ErrAddrSeq) {
+		t.Errorf("address overflow got error %v, want %v", err, info.ErrAddrSeq)
+	}
+
+	u, err := r.Seq(info.M_SP_NA_1, info.Spont, sys.MustObjAddrN(100), []byte{0}, []byte{1})
+	if err != nil {
+		t.Fatal("M_SP_NA_1 sequence error:", err)
+	}
+	var buf bytes.Buffer
+	if err := MonitorDataUnit(NewLogger(sys, &buf), u); err != nil {
+		t.Fatal("monitor error:", err)
+	}
+	const want = "M_SP_NA_1 spont 00 07/00:64 Off []\nM_SP_NA_1 spont 00 07/00:65 On []\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got logger output %q, want %q", got, want)
+	}
+
+	u.Type = info.M_SP_TA_1
+	if err := MonitorDataUnit(NewLogger(sys, &buf), u); !errors.Is(err, info.ErrAddrSeqType) {
+		t.Errorf("M_SP_TA_1 sequence got monitor error %v, want %v", err, info.ErrAddrSeqType)
+	}
+}
+
+func TestReportZeroObjAddr(t *testing.T) {
+	var sys info.System[info.OrigAddr0, info.ComAddr8, info.ObjAddr16]
+	r := testExchange().Report()
+	var zero info.ObjAddr16
+
+	if _, err := r.Object(info.M_SP_NA_1, info.Spont, zero, []byte{1}); !errors.Is(err, info.ErrObjAddrZero) {
+		t.Errorf("M_SP_NA_1 at address zero got error %v, want %v", err, info.ErrObjAddrZero)
+	}
+	if _, err := r.Seq(info.M_SP_NA_1, info.Spont, zero, []byte{1}); !errors.Is(err, info.ErrObjAddrZero) {
+		t.Errorf("M_SP_NA_1 sequence at address zero got error %v, want %v", err, info.ErrObjAddrZero)
+	}
+	if _, err := r.Object(info.M_EI_NA_1, info.Init, zero, []byte{0}); err != nil {
+		t.Errorf("M_EI_NA_1 at address zero got error %v", err)
+	}
+
+	r.ZeroObjAddr = true
+	u, err := r.Object(info.M_SP_NA_1, info.Spont, zero, []byte{1})
+	if err != nil {
+		t.Fatal("M_SP_NA_1 at address zero with ZeroObjAddr got error:", err)
+	}
+	var buf bytes.Buffer
+	if err := MonitorDataUnit(NewLogger(sys, &buf), u); err != nil {
+		t.Fatal("monitor error:", err)
+	}
+	const want = "M_SP_NA_1 spont 00 07/00:00 On []\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got logger output %q, want %q", got, want)
+	}
+}
+
+func TestArrivalTagger(t *testing.T) {
+	var sys info.System[info.OrigAddr0, info.ComAddr8, info.ObjAddr16]
+	r := testExchange().Report()
+
+	arrival := time.Date(2024, 2, 29, 13, 14, 15, 16e6, time.UTC)
+	decoded := time.Date(2024, 1, 2, 3, 4, 5, 6e6, time.UTC)
+
+	var got []time.Time
+	del := NewMonitorDelegate(sys)
+	del.SinglePtMonitor = SinglePtProxy(func(_ info.DataUnit[info.OrigAddr0, info.ComAddr8, info.ObjAddr16], _ info.ObjAddr16, _ info.SinglePtQual, t time.Time) {
+		got = append(got, t)
+	}, time.UTC, 0)
+	mon := NewArrivalTagger[info.OrigAddr0, info.ComAddr8, info.ObjAddr16](del, func() time.Time { return arrival })
+
+	plain, err := r.Object(info.M_SP_NA_1, info.Spont, sys.MustObjAddrN(1), []byte{1})
+	if err != nil {
+		t.Fatal("M_SP_NA_1 build error:", err)
+	}
+	var tag info.CP56Time2a
+	tag.Set(decoded)
+	tagged, err := r.Object(info.M_SP_TB_1, info.Spont, sys.MustObjAddrN(1), append([]byte{1}, tag[:]...))
+	if err != nil {
+		t.Fatal("M_SP_TB_1 build error:", err)
+	}
+
+	for _, u := range []info.DataUnit[info.OrigAddr0, info.ComAddr8, info.ObjAddr16]{plain, tagged} {
+		if err := MonitorDataUnit(mon, u); err != nil {
+			t.Fatalf("%s monitor error: %s", u.Type, err)
+		}
+	}
+	if len(got) != 2 || !got[0].Equal(arrival) || !got[1].Equal(decoded) {
+		t.Errorf("got times %s, want [%s %s]", got, arrival, decoded)
+	}
+}
+
+func TestMonitorTestFlag(t *testing.T) {
+	var sys info.System[info.OrigAddr0, info.ComAddr8, info.ObjAddr16]
+	r := testExchange().Report()
+
+	for _, c := range []info.Cause{info.Spont, info.Spont | info.TestFlag} {
+		u, err := r.Object(info.M_SP_NA_1, c, sys.MustObjAddrN(1), []byte{1})
+		if err != nil {
+			t.Fatal("M_SP_NA_1 build error:", err)
+		}
+
+		var calls int
+		del := NewMonitorDelegate(sys)
+		del.SinglePtMonitor = SinglePtProxy(func(u info.DataUnit[info.OrigAddr0, info.ComAddr8, info.ObjAddr16], _ info.ObjAddr16, _ info.SinglePtQual, _ time.Time) {
+			calls++
+			if want := c&info.TestFlag != 0; u.IsTest() != want {
+				t.Errorf("cause %s got IsTest %t, want %t", c, u.IsTest(), want)
+			}
+		}, time.UTC, 0)
+		if err := MonitorDataUnit(del, u); err != nil {
+			t.Fatal("monitor error:", err)
+		}
+		if calls != 1 {
+			t.Errorf("cause %s got %d calls, want 1", c, calls)
+		}
+	}
+}
+
+func TestPrivate(t *testing.T) {
+	var sys info.System[info.OrigAddr0, info.ComAddr8, info.ObjAddr16]
+	u := testExchange().NewDataUnit(info.TypeID(200), 1, info.Spont)
+	u.Info = append(u.Info, 1, 2, 3)
+
+	var buf bytes.Buffer
+	if err := MonitorDataUnit(NewLogger(sys, &buf), u); err != ErrNotMonitor {
+		t.Errorf("private type without handler got error %v, want %v", err, ErrNotMonitor)
+	}
+
+	var got []info.Header
+	mon := NewPrivate(NewLogger(sys, &buf), func(u info.DataUnit[info.OrigAddr0, info.ComAddr8, info.ObjAddr16]) {
+		got = append(got, u.Header())
+		if string(u.Info) != "\x01\x02\x03" {
+			t.Errorf("got payload %#x, want 0x010203", u.Info)
+		}
+	})
+	if err := MonitorDataUnit(mon, u); err != nil {
+		t.Fatal("private type got error:", err)
+	}
+	if len(got) != 1 || got[0].Type != 200 || got[0].Addr != 7 {
+		t.Errorf("got private calls %+v, want type 200 on common address 7", got)
+	}
+
+	// wrappers pass private types on
+	wrappers := map[string]func(Monitor[info.OrigAddr0, info.ComAddr8, info.ObjAddr16]) Monitor[info.OrigAddr0, info.ComAddr8, info.ObjAddr16]{
+		"ObjAddrMux": func(next Monitor[info.OrigAddr0, info.ComAddr8, info.ObjAddr16]) Monitor[info.OrigAddr0, info.ComAddr8, info.ObjAddr16] {
+			return NewObjAddrMux(next)
+		},
+		"ComAddrMux": func(next Monitor[info.OrigAddr0, info.ComAddr8, info.ObjAddr16]) Monitor[info.OrigAddr0, info.ComAddr8, info.ObjAddr16] {
+			mux := NewComAddrMux[info.OrigAddr0, info.ComAddr8, info.ObjAddr16](nil)
+			mux.Handle(sys.MustComAddrN(7), next)
+			return mux
+		},
+		"DelayCorrection": func(next Monitor[info.OrigAddr0, info.ComAddr8, info.ObjAddr16]) Monitor[info.OrigAddr0, info.ComAddr8, info.ObjAddr16] {
+			return NewDelayCorrection(next, time.UTC)
+		},
+		"ClockDrift": func(next Monitor[info.OrigAddr0, info.ComAddr8, info.ObjAddr16]) Monitor[info.OrigAddr0, info.ComAddr8, info.ObjAddr16] {
+			return NewClockDrift(next, nil, time.UTC, time.Second, nil)
+		},
+		"ArrivalTagger": func(next Monitor[info.OrigAddr0, info.ComAddr8, info.ObjAddr16]) Monitor[info.OrigAddr0, info.ComAddr8, info.ObjAddr16] {
+			return NewArrivalTagger(next, nil)
+		},
+	}
+	for name, wrap := range wrappers {
+		if err := MonitorDataUnit(wrap(NewLogger(sys, &buf)), u); err != ErrNotMonitor {
+			t.Errorf("%s without handler got error %v, want %v", name, err, ErrNotMonitor)
+		}
+		got = got[:0]
+		if err := MonitorDataUnit(wrap(mon), u); err != nil {
+			t.Errorf("%s got error: %s", name, err)
+		}
+		if len(got) != 1 {
+			t.Errorf("%s got %d private calls, want 1", name, len(got))
+		}
+	}
+}
+
+func TestEachTimed(t *testing.T) {
+	x := testExchange()
+
+	times := []time.Time{
+		time.Date(2024, 2, 29, 13, 14, 15, 16e6, time.UTC),
+		time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+	}
+	u := x.NewDataUnit(info.M_ME_TF_1, 2, info.Spont)
+	for i, ts := range times {
+		var tag info.CP56Time2a
+		tag.Set(ts)
+		u.Info = append(u.Info, byte(i+1), 0)                       // address
+		u.Info = append(u.Info, byte(i+1), byte(i+2), byte(i+3), 0) // float
+		u.Info = append(u.Info, 0)                                  // quality descriptor
+		u.Info = append(u.Info, tag[:]...)
+	}
+
+	var got []string
+	err := EachTimed(u, time.UTC, 0, func(addr info.ObjAddr16, raw []byte, t time.Time) {
+		got = append(got, fmt.Sprintf("%d %#x %s", addr.N(), raw, t.Format(time.RFC3339Nano)))
+	})
+	if err != nil {
+		t.Fatal("got error:", err)
+	}
+	want := []string{
+		"1 0x0102030000 2024-02-29T13:14:15.016Z",
+		"2 0x0203040000 2024-03-01T00:00:00Z",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	u.Info = u.Info[:len(u.Info)-1]
+	err = EachTimed(u, time.UTC, 0, func(info.ObjAddr16, []byte, time.Time) {
+		t.Error("invoked on malformed payload")
+	})
+	var decodeErr info.DecodeError
+	if !errors.As(err, &decodeErr) {
+		t.Errorf("malformed payload got error %v, want an info.DecodeError", err)
+	}
+
+	err = EachTimed(x.NewDataUnit(info.M_ME_NC_1, 0, info.Spont), time.UTC, 0, func(info.ObjAddr16, []byte, time.Time) {})
+	if err != ErrNotTimed {
+		t.Errorf("M_ME_NC_1 got error %v, want %v", err, ErrNotTimed)
+	}
+}
+
+func TestAppendObject(t *testing.T) {
+	var sys info.System[info.OrigAddr0, info.ComAddr8, info.ObjAddr16]
+	x := testExchange()
+
+	list := x.NewDataUnit(info.M_ME_NB_1, 0, info.Cyclic)
+	seq := x.NewDataUnit(info.M_ME_NB_1, 0x80, info.Cyclic)
+	for _, n := range []uint{10, 11, 12} {
+		element := []byte{byte(n), 0, 0}
+		if err := list.AppendObject(sys.MustObjAddrN(n*2), element); err != nil {
+			t.Fatal("list append error:", err)
+		}
+		if err := seq.AppendObject(sys.MustObjAddrN(n), element); err != nil {
+			t.Fatal("sequence append error:", err)
+		}
+	}
+	if err := seq.AppendObject(sys.MustObjAddrN(99), []byte{99, 0, 0}); err == nil {
+		t.Error("sequence append of address 99 after 12 got no error")
+	}
+	if err := seq.AppendObject(sys.MustObjAddrN(13), []byte{13, 0}); err == nil {
+		t.Error("append of short element got no error")
+	}
+
+	var buf bytes.Buffer
+	for _, u := range []info.DataUnit[info.OrigAddr0, info.ComAddr8, info.ObjAddr16]{list, seq} {
+		if u.Enc.Count() != 3 {
+			t.Errorf("got %d objects, want 3", u.Enc.Count())
+		}
+		if err := MonitorDataUnit(NewLogger(sys, &buf), u); err != nil {
+			t.Fatal("monitor error:", err)
+		}
+	}
+	const want = "M_ME_NB_1 cyclic 00 07/00:14 10 []\n" +
+		"M_ME_NB_1 cyclic 00 07/00:16 11 []\n" +
+		"M_ME_NB_1 cyclic 00 07/00:18 12 []\n" +
+		"M_ME_NB_1 cyclic 00 07/00:0a 10 []\n" +
+		"M_ME_NB_1 cyclic 00 07/00:0b 11 []\n" +
+		"M_ME_NB_1 cyclic 00 07/00:0c 12 []\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got log %q, want %q", got, want)
+	}
+}
+
+// The size table of info.ElemSize must match the parser.
+func TestElemSizeParser(t *testing.T) {
+	var sys info.System[info.OrigAddr0, info.ComAddr8, info.ObjAddr16]
+	x := testExchange()
+	mon := NewMonitorDelegate(sys)
+
+	var covered int
+	for i := 1; i <= 44; i++ {
+		typ := info.TypeID(i)
+		size, ok := info.ElemSize(typ)
+		if !ok {
+			u := x.NewDataUnit(typ, 1, info.Spont)
+			u.Info = make([]byte, 2+1)
+			if err := MonitorDataUnit(mon, u); err != ErrMonitorReserve {
+				t.Errorf("%s has no size, yet the parser got error %v", typ, err)
+			}
+			continue
+		}
+		covered++
+
+		counts := []int{1, 2}
+		switch typ {
+		case info.M_EP_TB_1, info.M_EP_TC_1, info.M_EP_TE_1, info.M_EP_TF_1:
+			counts = counts[:1] // packed events
+		}
+		for _, count := range counts {
+			stride := 2 + size // address plus element
+			u := x.NewDataUnit(typ, info.Enc(count), info.Spont)
+			u.Info = make([]byte, count*stride)
+			if err := MonitorDataUnit(mon, u); err != nil {
+				t.Errorf("%s of %d objects with %d octets each got error: %s", typ, count, stride, err)
+			}
+
+			for _, n := range []int{count*stride - 1, count*stride + 1} {
+				u.Info = make([]byte, n)
+				if err := MonitorDataUnit(mon, u); !errors.Is(err, errInfoSize) {
+					t.Errorf("%s of %d objects with %d octets in total got error %v, want %v", typ, count, n, err, errInfoSize)
+				}
+			}
+		}
+	}
+	// reserved: 22..29 and 41..44
+	if covered != 44-12 {
+		t.Errorf("got %d monitor types in the size table, want %d", covered, 44-12)
+	}
+}
@@ -0,0 +1,352 @@
+package part5
+
+import "github.com/pascaldekloe/part5/info"
+
+// ObjAddrMux routes information in monitor direction per information-object
+// address. Addresses without registration go to the default Monitor.
+type ObjAddrMux[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr] struct {
+	def    Monitor[Orig, Com, Obj]
+	routes map[Obj]Monitor[Orig, Com, Obj]
+}
+
+// NewObjAddrMux returns a new multiplexer with a def(ault) for each address
+// which is not registered. InitEnd always goes to the default, as do private
+// type identifiers. Note that def may be nil for silent discards.
+func NewObjAddrMux[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr](def Monitor[Orig, Com, Obj]) *ObjAddrMux[Orig, Com, Obj] {
+	if def == nil {
+		def = NewMonitorDelegate(info.System[Orig, Com, Obj]{})
+	}
+	return &ObjAddrMux[Orig, Com, Obj]{
+		def:    def,
+		routes: make(map[Obj]Monitor[Orig, Com, Obj]),
+	}
+}
+
+// Handle registers mon for addr, replacing any previous registration. Nil
+// removes the registration. Handle is not safe for use concurrent with the
+// Monitor methods.
+func (mux *ObjAddrMux[Orig, Com, Obj]) Handle(addr Obj, mon Monitor[Orig, Com, Obj]) {
+	if mon == nil {
+		delete(mux.routes, addr)
+	} else {
+		mux.routes[addr] = mon
+	}
+}
+
+func (mux *ObjAddrMux[Orig, Com, Obj]) route(addr Obj) Monitor[Orig, Com, Obj] {
+	if mon, ok := mux.routes[addr]; ok {
+		return mon
+	}
+	return mux.def
+}
+
+func (mux *ObjAddrMux[Orig, Com, Obj]) SinglePt(u info.DataUnit[Orig, Com, Obj], addr Obj, p info.SinglePtQual) {
+	mux.route(addr).SinglePt(u, addr, p)
+}
+
+func (mux *ObjAddrMux[Orig, Com, Obj]) SinglePtAtMinute(u info.DataUnit[Orig, Com, Obj], addr Obj, p info.SinglePtQual, tag info.CP24Time2a) {
+	mux.route(addr).SinglePtAtMinute(u, addr, p, tag)
+}
+
+func (mux *ObjAddrMux[Orig, Com, Obj]) SinglePtAtMoment(u info.DataUnit[Orig, Com, Obj], addr Obj, p info.SinglePtQual, tag info.CP56Time2a) {
+	mux.route(addr).SinglePtAtMoment(u, addr, p, tag)
+}
+
+func (mux *ObjAddrMux[Orig, Com, Obj]) SinglePtChangePack(u info.DataUnit[Orig, Com, Obj], addr Obj, pack info.SinglePtChangePack, q info.Qual) {
+	mux.route(addr).SinglePtChangePack(u, addr, pack, q)
+}
+
+func (mux *ObjAddrMux[Orig, Com, Obj]) DoublePt(u info.DataUnit[Orig, Com, Obj], addr Obj, p info.DoublePtQual) {
+	mux.route(addr).DoublePt(u, addr, p)
+}
+
+func (mux *ObjAddrMux[Orig, Com, Obj]) DoublePtAtMinute(u info.DataUnit[Orig, Com, Obj], addr Obj, p info.DoublePtQual, tag info.CP24Time2a) {
+	mux.route(addr).DoublePtAtMinute(u, addr, p, tag)
+}
+
+func (mux *ObjAddrMux[Orig, Com, Obj]) DoublePtAtMoment(u info.DataUnit[Orig, Com, Obj], addr Obj, p info.DoublePtQual, tag info.CP56Time2a) {
+	mux.route(addr).DoublePtAtMoment(u, addr, p, tag)
+}
+
+func (mux *ObjAddrMux[Orig, Com, Obj]) Step(u info.DataUnit[Orig, Com, Obj], addr Obj, p info.StepQual) {
+	mux.route(addr).Step(u, addr, p)
+}
+
+func (mux *ObjAddrMux[Orig, Com, Obj]) StepAtMinute(u info.DataUnit[Orig, Com, Obj], addr Obj, p info.StepQual, tag info.CP24Time2a) {
+	mux.route(addr).StepAtMinute(u, addr, p, tag)
+}
+
+func (mux *ObjAddrMux[Orig, Com, Obj]) StepAtMoment(u info.DataUnit[Orig, Com, Obj], addr Obj, p info.StepQual, tag info.CP56Time2a) {
+	mux.route(addr).StepAtMoment(u, addr, p, tag)
+}
+
+func (mux *ObjAddrMux[Orig, Com, Obj]) Bits(u info.DataUnit[Orig, Com, Obj], addr Obj, b info.BitsQual) {
+	mux.route(addr).Bits(u, addr, b)
+}
+
+func (mux *ObjAddrMux[Orig, Com, Obj]) BitsAtMinute(u info.DataUnit[Orig, Com, Obj], addr Obj, b info.BitsQual, tag info.CP24Time2a) {
+	mux.route(addr).BitsAtMinute(u, addr, b, tag)
+}
+
+func (mux *ObjAddrMux[Orig, Com, Obj]) BitsAtMoment(u info.DataUnit[Orig, Com, Obj], addr Obj, b info.BitsQual, tag info.CP56Time2a) {
+	mux.route(addr).BitsAtMoment(u, addr, b, tag)
+}
+
+func (mux *ObjAddrMux[Orig, Com, Obj]) NormUnqual(u info.DataUnit[Orig, Com, Obj], addr Obj, n info.Norm) {
+	mux.route(addr).NormUnqual(u, addr, n)
+}
+
+func (mux *ObjAddrMux[Orig, Com, Obj]) Norm(u info.DataUnit[Orig, Com, Obj], addr Obj, n info.NormQual) {
+	mux.route(addr).Norm(u, addr, n)
+}
+
+func (mux *ObjAddrMux[Orig, Com, Obj]) NormAtMinute(u info.DataUnit[Orig, Com, Obj], addr Obj, n info.NormQual, tag info.CP24Time2a) {
+	mux.route(addr).NormAtMinute(u, addr, n, tag)
+}
+
+func (mux *ObjAddrMux[Orig, Com, Obj]) NormAtMoment(u info.DataUnit[Orig, Com, Obj], addr Obj, n info.NormQual, tag info.CP56Time2a) {
+	mux.route(addr).NormAtMoment(u, addr, n, tag)
+}
+
+func (mux *ObjAddrMux[Orig, Com, Obj]) Scaled(u info.DataUnit[Orig, Com, Obj], addr Obj, v int16, q info.Qual) {
+	mux.route(addr).Scaled(u, addr, v, q)
+}
+
+func (mux *ObjAddrMux[Orig, Com, Obj]) ScaledAtMinute(u info.DataUnit[Orig, Com, Obj], addr Obj, v int16, q info.Qual, tag info.CP24Time2a) {
+	mux.route(addr).ScaledAtMinute(u, addr, v, q, tag)
+}
+
+func (mux *ObjAddrMux[Orig, Com, Obj]) ScaledAtMoment(u info.DataUnit[Orig, Com, Obj], addr Obj, v int16, q info.Qual, tag info.CP56Time2a) {
+	mux.route(addr).ScaledAtMoment(u, addr, v, q, tag)
+}
+
+func (mux *ObjAddrMux[Orig, Com, Obj]) Float(u info.DataUnit[Orig, Com, Obj], addr Obj, f float32, q info.Qual) {
+	mux.route(addr).Float(u, addr, f, q)
+}
+
+func (mux *ObjAddrMux[Orig, Com, Obj]) FloatAtMinute(u info.DataUnit[Orig, Com, Obj], addr Obj, f float32, q info.Qual, tag info.CP24Time2a) {
+	mux.route(addr).FloatAtMinute(u, addr, f, q, tag)
+}
+
+func (mux *ObjAddrMux[Orig, Com, Obj]) FloatAtMoment(u info.DataUnit[Orig, Com, Obj], addr Obj, f float32, q info.Qual, tag info.CP56Time2a) {
+	mux.route(addr).FloatAtMoment(u, addr, f, q, tag)
+}
+
+func (mux *ObjAddrMux[Orig, Com, Obj]) Totals(u info.DataUnit[Orig, Com, Obj], addr Obj, c info.Counter) {
+	mux.route(addr).Totals(u, addr, c)
+}
+
+func (mux *ObjAddrMux[Orig, Com, Obj]) TotalsAtMinute(u info.DataUnit[Orig, Com, Obj], addr Obj, c info.Counter, tag info.CP24Time2a) {
+	mux.route(addr).TotalsAtMinute(u, addr, c, tag)
+}
+
+func (mux *ObjAddrMux[Orig, Com, Obj]) TotalsAtMoment(u info.DataUnit[Orig, Com, Obj], addr Obj, c info.Counter, tag info.CP56Time2a) {
+	mux.route(addr).TotalsAtMoment(u, addr, c, tag)
+}
+
+func (mux *ObjAddrMux[Orig, Com, Obj]) ProtectAtMinute(u info.DataUnit[Orig, Com, Obj], addr Obj, e info.ProtectEvent, tag info.CP24Time2a) {
+	mux.route(addr).ProtectAtMinute(u, addr, e, tag)
+}
+
+func (mux *ObjAddrMux[Orig, Com, Obj]) ProtectAtMoment(u info.DataUnit[Orig, Com, Obj], addr Obj, e info.ProtectEvent, tag info.CP56Time2a) {
+	mux.route(addr).ProtectAtMoment(u, addr, e, tag)
+}
+
+func (mux *ObjAddrMux[Orig, Com, Obj]) ProtectStartAtMinute(u info.DataUnit[Orig, Com, Obj], addr Obj, e info.ProtectStartEvent, tag info.CP24Time2a) {
+	mux.route(addr).ProtectStartAtMinute(u, addr, e, tag)
+}
+
+func (mux *ObjAddrMux[Orig, Com, Obj]) ProtectStartAtMoment(u info.DataUnit[Orig, Com, Obj], addr Obj, e info.ProtectStartEvent, tag info.CP56Time2a) {
+	mux.route(addr).ProtectStartAtMoment(u, addr, e, tag)
+}
+
+func (mux *ObjAddrMux[Orig, Com, Obj]) ProtectOutAtMinute(u info.DataUnit[Orig, Com, Obj], addr Obj, e info.ProtectOutEvent, tag info.CP24Time2a) {
+	mux.route(addr).ProtectOutAtMinute(u, addr, e, tag)
+}
+
+func (mux *ObjAddrMux[Orig, Com, Obj]) ProtectOutAtMoment(u info.DataUnit[Orig, Com, Obj], addr Obj, e info.ProtectOutEvent, tag info.CP56Time2a) {
+	mux.route(addr).ProtectOutAtMoment(u, addr, e, tag)
+}
+
+func (mux *ObjAddrMux[Orig, Com, Obj]) InitEnd(u info.DataUnit[Orig, Com, Obj], c info.InitCause) {
+	mux.def.InitEnd(u, c)
+}
+
+// Private implements the PrivateMonitor interface.
+func (mux *ObjAddrMux[Orig, Com, Obj]) Private(u info.DataUnit[Orig, Com, Obj]) error {
+	return monitorPrivate(mux.def, u)
+}
+
+// ComAddrMux routes information in monitor direction per common address, i.e.,
+// per station. Addresses without registration go to the default Monitor.
+type ComAddrMux[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr] struct {
+	def    Monitor[Orig, Com, Obj]
+	routes map[Com]Monitor[Orig, Com, Obj]
+}
+
+// NewComAddrMux returns a new multiplexer with a def(ault) for each address
+// which is not registered. Note that def may be nil for silent discards.
+func NewComAddrMux[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr](def Monitor[Orig, Com, Obj]) *ComAddrMux[Orig, Com, Obj] {
+	if def == nil {
+		def = NewMonitorDelegate(info.System[Orig, Com, Obj]{})
+	}
+	return &ComAddrMux[Orig, Com, Obj]{
+		def:    def,
+		routes: make(map[Com]Monitor[Orig, Com, Obj]),
+	}
+}
+
+// Handle registers mon for addr, replacing any previous registration. Nil
+// removes the registration. Handle is not safe for use concurrent with the
+// Monitor methods.
+func (mux *ComAddrMux[Orig, Com, Obj]) Handle(addr Com, mon Monitor[Orig, Com, Obj]) {
+	if mon == nil {
+		delete(mux.routes, addr)
+	} else {
+		mux.routes[addr] = mon
+	}
+}
+
+func (mux *ComAddrMux[Orig, Com, Obj]) route(addr Com) Monitor[Orig, Com, Obj] {
+	if mon, ok := mux.routes[addr]; ok {
+		return mon
+	}
+	return mux.def
+}
+
+func (mux *ComAddrMux[Orig, Com, Obj]) SinglePt(u info.DataUnit[Orig, Com, Obj], addr Obj, p info.SinglePtQual) {
+	mux.route(u.Addr).SinglePt(u, addr, p)
+}
+
+func (mux *ComAddrMux[Orig, Com, Obj]) SinglePtAtMinute(u info.DataUnit[Orig, Com, Obj], addr Obj, p info.SinglePtQual, tag info.CP24Time2a) {
+	mux.route(u.Addr).SinglePtAtMinute(u, addr, p, tag)
+}
+
+func (mux *ComAddrMux[Orig, Com, Obj]) SinglePtAtMoment(u info.DataUnit[Orig, Com, Obj], addr Obj, p info.SinglePtQual, tag info.CP56Time2a) {
+	mux.route(u.Addr).SinglePtAtMoment(u, addr, p, tag)
+}
+
+func (mux *ComAddrMux[Orig, Com, Obj]) SinglePtChangePack(u info.DataUnit[Orig, Com, Obj], addr Obj, pack info.SinglePtChangePack, q info.Qual) {
+	mux.route(u.Addr).SinglePtChangePack(u, addr, pack, q)
+}
+
+func (mux *ComAddrMux[Orig, Com, Obj]) DoublePt(u info.DataUnit[Orig, Com, Obj], addr Obj, p info.DoublePtQual) {
+	mux.route(u.Addr).DoublePt(u, addr, p)
+}
+
+func (mux *ComAddrMux[Orig, Com, Obj]) DoublePtAtMinute(u info.DataUnit[Orig, Com, Obj], addr Obj, p info.DoublePtQual, tag info.CP24Time2a) {
+	mux.route(u.Addr).DoublePtAtMinute(u, addr, p, tag)
+}
+
+func (mux *ComAddrMux[Orig, Com, Obj]) DoublePtAtMoment(u info.DataUnit[Orig, Com, Obj], addr Obj, p info.DoublePtQual, tag info.CP56Time2a) {
+	mux.route(u.Addr).DoublePtAtMoment(u, addr, p, tag)
+}
+
+func (mux *ComAddrMux[Orig, Com, Obj]) Step(u info.DataUnit[Orig, Com, Obj], addr Obj, p info.StepQual) {
+	mux.route(u.Addr).Step(u, addr, p)
+}
+
+func (mux *ComAddrMux[Orig, Com, Obj]) StepAtMinute(u info.DataUnit[Orig, Com, Obj], addr Obj, p info.StepQual, tag info.CP24Time2a) {
+	mux.route(u.Addr).StepAtMinute(u, addr, p, tag)
+}
+
+func (mux *ComAddrMux[Orig, Com, Obj]) StepAtMoment(u info.DataUnit[Orig, Com, Obj], addr Obj, p info.StepQual, tag info.CP56Time2a) {
+	mux.route(u.Addr).StepAtMoment(u, addr, p, tag)
+}
+
+func (mux *ComAddrMux[Orig, Com, Obj]) Bits(u info.DataUnit[Orig, Com, Obj], addr Obj, b info.BitsQual) {
+	mux.route(u.Addr).Bits(u, addr, b)
+}
+
+func (mux *ComAddrMux[Orig, Com, Obj]) BitsAtMinute(u info.DataUnit[Orig, Com, Obj], addr Obj, b info.BitsQual, tag info.CP24Time2a) {
+	mux.route(u.Addr).BitsAtMinute(u, addr, b, tag)
+}
+
+func (mux *ComAddrMux[Orig, Com, Obj]) BitsAtMoment(u info.DataUnit[Orig, Com, Obj], addr Obj, b info.BitsQual, tag info.CP56Time2a) {
+	mux.route(u.Addr).BitsAtMoment(u, addr, b, tag)
+}
+
+func (mux *ComAddrMux[Orig, Com, Obj]) NormUnqual(u info.DataUnit[Orig, Com, Obj], addr Obj, n info.Norm) {
+	mux.route(u.Addr).NormUnqual(u, addr, n)
+}
+
+func (mux *ComAddrMux[Orig, Com, Obj]) Norm(u info.DataUnit[Orig, Com, Obj], addr Obj, n info.NormQual) {
+	mux.route(u.Addr).Norm(u, addr, n)
+}
+
+func (mux *ComAddrMux[Orig, Com, Obj]) NormAtMinute(u info.DataUnit[Orig, Com, Obj], addr Obj, n info.NormQual, tag info.CP24Time2a) {
+	mux.route(u.Addr).NormAtMinute(u, addr, n, tag)
+}
+
+func (mux *ComAddrMux[Orig, Com, Obj]) NormAtMoment(u info.DataUnit[Orig, Com, Obj], addr Obj, n info.NormQual, tag info.CP56Time2a) {
+	mux.route(u.Addr).NormAtMoment(u, addr, n, tag)
+}
+
+func (mux *ComAddrMux[Orig, Com, Obj]) Scaled(u info.DataUnit[Orig, Com, Obj], addr Obj, v int16, q info.Qual) {
+	mux.route(u.Addr).Scaled(u, addr, v, q)
+}
+
+func (mux *ComAddrMux[Orig, Com, Obj]) ScaledAtMinute(u info.DataUnit[Orig, Com, Obj], addr Obj, v int16, q info.Qual, tag info.CP24Time2a) {
+	mux.route(u.Addr).ScaledAtMinute(u, addr, v, q, tag)
+}
+
+func (mux *ComAddrMux[Orig, Com, Obj]) ScaledAtMoment(u info.DataUnit[Orig, Com, Obj], addr Obj, v int16, q info.Qual, tag info.CP56Time2a) {
+	mux.route(u.Addr).ScaledAtMoment(u, addr, v, q, tag)
+}
+
+func (mux *ComAddrMux[Orig, Com, Obj]) Float(u info.DataUnit[Orig, Com, Obj], addr Obj, f float32, q info.Qual) {
+	mux.route(u.Addr).Float(u, addr, f, q)
+}
+
+func (mux *ComAddrMux[Orig, Com, Obj]) FloatAtMinute(u info.DataUnit[Orig, Com, Obj], addr Obj, f float32, q info.Qual, tag info.CP24Time2a) {
+	mux.route(u.Addr).FloatAtMinute(u, addr, f, q, tag)
+}
+
+func (mux *ComAddrMux[Orig, Com, Obj]) FloatAtMoment(u info.DataUnit[Orig, Com, Obj], addr Obj, f float32, q info.Qual, tag info.CP56Time2a) {
+	mux.route(u.Addr).FloatAtMoment(u, addr, f, q, tag)
+}
+
+func (mux *ComAddrMux[Orig, Com, Obj]) Totals(u info.DataUnit[Orig, Com, Obj], addr Obj, c info.Counter) {
+	mux.route(u.Addr).Totals(u, addr, c)
+}
+
+func (mux *ComAddrMux[Orig, Com, Obj]) TotalsAtMinute(u info.DataUnit[Orig, Com, Obj], addr Obj, c info.Counter, tag info.CP24Time2a) {
+	mux.route(u.Addr).TotalsAtMinute(u, addr, c, tag)
+}
+
+func (mux *ComAddrMux[Orig, Com, Obj]) TotalsAtMoment(u info.DataUnit[Orig, Com, Obj], addr Obj, c info.Counter, tag info.CP56Time2a) {
+	mux.route(u.Addr).TotalsAtMoment(u, addr, c, tag)
+}
+
+func (mux *ComAddrMux[Orig, Com, Obj]) ProtectAtMinute(u info.DataUnit[Orig, Com, Obj], addr Obj, e info.ProtectEvent, tag info.CP24Time2a) {
+	mux.route(u.Addr).ProtectAtMinute(u, addr, e, tag)
+}
+
+func (mux *ComAddrMux[Orig, Com, Obj]) ProtectAtMoment(u info.DataUnit[Orig, Com, Obj], addr Obj, e info.ProtectEvent, tag info.CP56Time2a) {
+	mux.route(u.Addr).ProtectAtMoment(u, addr, e, tag)
+}
+
+func (mux *ComAddrMux[Orig, Com, Obj]) ProtectStartAtMinute(u info.DataUnit[Orig, Com, Obj], addr Obj, e info.ProtectStartEvent, tag info.CP24Time2a) {
+	mux.route(u.Addr).ProtectStartAtMinute(u, addr, e, tag)
+}
+
+func (mux *ComAddrMux[Orig, Com, Obj]) ProtectStartAtMoment(u info.DataUnit[Orig, Com, Obj], addr Obj, e info.ProtectStartEvent, tag info.CP56Time2a) {
+	mux.route(u.Addr).ProtectStartAtMoment(u, addr, e, tag)
+}
+
+func (mux *ComAddrMux[Orig, Com, Obj]) ProtectOutAtMinute(u info.DataUnit[Orig, Com, Obj], addr Obj, e info.ProtectOutEvent, tag info.CP24Time2a) {
+	mux.route(u.Addr).ProtectOutAtMinute(u, addr, e, tag)
+}
+
+func (mux *ComAddrMux[Orig, Com, Obj]) ProtectOutAtMoment(u info.DataUnit[Orig, Com, Obj], addr Obj, e info.ProtectOutEvent, tag info.CP56Time2a) {
+	mux.route(u.Addr).ProtectOutAtMoment(u, addr, e, tag)
+}
+
+func (mux *ComAddrMux[Orig, Com, Obj]) InitEnd(u info.DataUnit[Orig, Com, Obj], c info.InitCause) {
+	mux.route(u.Addr).InitEnd(u, c)
+}
+
+// Private implements the PrivateMonitor interface.
+func (mux *ComAddrMux[Orig, Com, Obj]) Private(u info.DataUnit[Orig, Com, Obj]) error {
+	return monitorPrivate(mux.route(u.Addr), u)
+}
@@ -0,0 +1,73 @@
+package part5
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pascaldekloe/part5/info"
+)
+
+func TestAddrMux(t *testing.T) {
+	x := testExchange()
+	sys := x.System
+
+	golden := []struct {
+		name string
+		// routes to a and b with def(ault)
+		mux func(def, a, b Monitor[info.OrigAddr0, info.ComAddr8, info.ObjAddr16]) Monitor[info.OrigAddr0, info.ComAddr8, info.ObjAddr16]
+		// common and object address per unit for a, b and def
+		comAddrs, objAddrs [3]uint
+	}{
+		{
+			"ObjAddrMux",
+			func(def, a, b Monitor[info.OrigAddr0, info.ComAddr8, info.ObjAddr16]) Monitor[info.OrigAddr0, info.ComAddr8, info.ObjAddr16] {
+				mux := NewObjAddrMux(def)
+				mux.Handle(sys.MustObjAddrN(100), a)
+				mux.Handle(sys.MustObjAddrN(200), b)
+				return mux
+			},
+			[3]uint{7, 7, 7}, [3]uint{100, 200, 300},
+		},
+		{
+			"ComAddrMux",
+			func(def, a, b Monitor[info.OrigAddr0, info.ComAddr8, info.ObjAddr16]) Monitor[info.OrigAddr0, info.ComAddr8, info.ObjAddr16] {
+				mux := NewComAddrMux(def)
+				mux.Handle(sys.MustComAddrN(1), a)
+				mux.Handle(sys.MustComAddrN(2), b)
+				return mux
+			},
+			[3]uint{1, 2, 3}, [3]uint{100, 100, 100},
+		},
+	}
+
+	for _, gold := range golden {
+		// common and object address per call
+		got := make(map[string][][2]uint)
+		handler := func(name string) Monitor[info.OrigAddr0, info.ComAddr8, info.ObjAddr16] {
+			del := NewMonitorDelegate(sys)
+			del.FloatMonitor = FloatProxy(func(u info.DataUnit[info.OrigAddr0, info.ComAddr8, info.ObjAddr16], addr info.ObjAddr16, _ float32, _ info.Qual, _ time.Time) {
+				got[name] = append(got[name], [2]uint{u.Addr.N(), addr.N()})
+			}, time.UTC, 0)
+			return del
+		}
+		mux := gold.mux(handler("default"), handler("a"), handler("b"))
+
+		for i := range gold.comAddrs {
+			x.ComAddr = sys.MustComAddrN(gold.comAddrs[i])
+			u, err := x.Report().Object(info.M_ME_NC_1, info.Spont, sys.MustObjAddrN(gold.objAddrs[i]), []byte{0, 0, 0x80, 0x3f, 0})
+			if err != nil {
+				t.Fatal("M_ME_NC_1 build error:", err)
+			}
+			if err := MonitorDataUnit(mux, u); err != nil {
+				t.Fatalf("%s: monitor error: %s", gold.name, err)
+			}
+		}
+
+		for i, name := range []string{"a", "b", "default"} {
+			want := [2]uint{gold.comAddrs[i], gold.objAddrs[i]}
+			if len(got[name]) != 1 || got[name][0] != want {
+				t.Errorf("%s: handler %q got common and object addresses %d, want [%d]", gold.name, name, got[name], want)
+			}
+		}
+	}
+}
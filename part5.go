@@ -8,6 +8,11 @@
 // single method, regardless of the time tag. Such hyrachical setup allows users
 // to write for the applicable types only. MonitorDelegate can default to a
 // Logger or some equivalent to detect unsupported types.
+//
+// # Controller
+//
+// The Controller interface holds listeners for commands, i.e., the type codes
+// with a "C_" prefix, in the same way as Monitor does for measured values.
 package part5
 
 import (
@@ -120,3 +125,49 @@ func ConOf[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr](in, req info.
 	}
 	return CauseMis{Type: req.Type, Req: req.Cause, Res: in.Cause}
 }
+
+// ConfirmPositive returns the positive confirmation of a command request, i.e.,
+// info.Actcon on info.Act, and info.Deactcon on info.Deact. The TestFlag from
+// the request is preserved.
+func ConfirmPositive[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr](req info.DataUnit[Orig, Com, Obj]) info.DataUnit[Orig, Com, Obj] {
+	return mirror(req, confCause(req.Cause))
+}
+
+// ConfirmNegative returns the negative confirmation of a command request, i.e.,
+// info.Actcon or info.Deactcon with the info.NegFlag set. The TestFlag from the
+// request is preserved.
+func ConfirmNegative[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr](req info.DataUnit[Orig, Com, Obj]) info.DataUnit[Orig, Com, Obj] {
+	return mirror(req, confCause(req.Cause)|info.NegFlag)
+}
+
+// Reject returns the mirror of a request with an unknown type identifier, cause
+// of transmission, common address or information-object address. The reason
+// should be one of info.UnkType, info.UnkCause, info.UnkAddr or info.UnkInfo.
+// The NegFlag is set, and the TestFlag from the request is preserved. See
+// chapter 7.2.3 of companion standard 101.
+func Reject[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr](req info.DataUnit[Orig, Com, Obj], reason info.Cause) info.DataUnit[Orig, Com, Obj] {
+	return mirror(req, reason&^info.TestFlag|info.NegFlag)
+}
+
+// Terminate returns the termination of a command request, i.e., info.Actterm.
+// The TestFlag from the request is preserved.
+func Terminate[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr](req info.DataUnit[Orig, Com, Obj]) info.DataUnit[Orig, Com, Obj] {
+	return mirror(req, info.Actterm)
+}
+
+// ConfCause returns the confirmation cause of the request cause, without flags.
+func confCause(req info.Cause) info.Cause {
+	if req&^(info.TestFlag|info.NegFlag) == info.Deact {
+		return info.Deactcon
+	}
+	return info.Actcon
+}
+
+// Mirror returns a copy of req with cause c, plus the TestFlag from req.
+func mirror[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr](req info.DataUnit[Orig, Com, Obj], c info.Cause) info.DataUnit[Orig, Com, Obj] {
+	res := req
+	// detach from request buffer
+	res.Info = append([]byte(nil), req.Info...)
+	res.Cause = c | req.Cause&info.TestFlag
+	return res
+}
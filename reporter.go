@@ -0,0 +1,133 @@
+package part5
+
+import (
+	"encoding/binary"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pascaldekloe/part5/info"
+)
+
+// AnalogReporter emits measured values, i.e., short floating points, per
+// information-object address. Changes beyond a deadband are reported with
+// cause info.Spont. Values which are not reported within an interval are
+// reported with cause info.Cyclic on the next Cycle. All reports use type
+// M_ME_NC_1, conform chapter 7.3.1.13 of companion standard 101.
+type AnalogReporter[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr] struct {
+	report   Report[Orig, Com, Obj]
+	deadband float32
+	interval time.Duration
+	clock    func() time.Time
+	emit     func(info.DataUnit[Orig, Com, Obj])
+
+	mutex  sync.Mutex
+	points map[Obj]*analogPoint
+}
+
+// AnalogPoint is the state of an information object.
+type analogPoint struct {
+	value float32
+	qual  info.Qual
+
+	// last report
+	reported     float32
+	reportedQual info.Qual
+	reportedAt   time.Time
+}
+
+// NewAnalogReporter returns a new reporter which passes each ASDU to emit. The
+// deadband is the minimum absolute change for spontaneous reports. The interval
+// is the maximum amount of time between reports per address. The clock defaults
+// to time.Now when nil. Emit is called with a lock held, such that reports
+// arrive in order of Update and Cycle. Emit must not call back into the
+// reporter.
+func NewAnalogReporter[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr](r Report[Orig, Com, Obj], deadband float32, interval time.Duration, clock func() time.Time, emit func(info.DataUnit[Orig, Com, Obj])) *AnalogReporter[Orig, Com, Obj] {
+	if clock == nil {
+		clock = time.Now
+	}
+	return &AnalogReporter[Orig, Com, Obj]{
+		report:   r,
+		deadband: deadband,
+		interval: interval,
+		clock:    clock,
+		emit:     emit,
+		points:   make(map[Obj]*analogPoint),
+	}
+}
+
+func floatElement(value float32, q info.Qual) []byte {
+	var element [5]byte
+	binary.LittleEndian.PutUint32(element[:4], math.Float32bits(value))
+	element[4] = byte(q)
+	return element[:]
+}
+
+// Update sets the current value of an information object. The first update of
+// an address, any change in quality, and any change in value beyond the
+// deadband are reported spontaneously.
+func (a *AnalogReporter[Orig, Com, Obj]) Update(addr Obj, value float32, q info.Qual) error {
+	now := a.clock()
+
+	a.mutex.Lock()
+	p, ok := a.points[addr]
+	if !ok {
+		p = new(analogPoint)
+		a.points[addr] = p
+	}
+	p.value, p.qual = value, q
+	delta := float64(value) - float64(p.reported)
+	if ok && q == p.reportedQual && math.Abs(delta) <= float64(a.deadband) {
+		a.mutex.Unlock()
+		return nil // within deadband
+	}
+	u, err := a.report.Object(info.M_ME_NC_1, info.Spont, addr, floatElement(value, q))
+	if err != nil {
+		delete(a.points, addr)
+		a.mutex.Unlock()
+		return err
+	}
+	p.reported, p.reportedQual, p.reportedAt = value, q, now
+
+	// emit in order of state
+	a.emit(u)
+	a.mutex.Unlock()
+	return nil
+}
+
+// Cycle reports the current value of each information object which was not
+// reported within the interval. Cycle should be called periodically, with
+// a fraction of the interval for accuracy. Objects are sorted by address, and
+// packed up to 127 per ASDU.
+func (a *AnalogReporter[Orig, Com, Obj]) Cycle() {
+	now := a.clock()
+
+	a.mutex.Lock()
+	var due []Obj
+	for addr, p := range a.points {
+		if now.Sub(p.reportedAt) >= a.interval {
+			due = append(due, addr)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].N() < due[j].N() })
+
+	var units []info.DataUnit[Orig, Com, Obj]
+	for _, addr := range due {
+		if len(units) == 0 || units[len(units)-1].Enc.Count() >= 127 {
+			units = append(units, a.report.NewDataUnit(info.M_ME_NC_1, 0, info.Cyclic))
+		}
+		p := a.points[addr]
+		// The error return is nil because M_ME_NC_1 has a fixed
+		// element size of 5 octets, the count stays below 127, and
+		// the SQ flag is off. Update rejected any invalid address.
+		_ = units[len(units)-1].AppendObject(addr, floatElement(p.value, p.qual))
+		p.reported, p.reportedQual, p.reportedAt = p.value, p.qual, now
+	}
+
+	// emit in order of state
+	for _, u := range units {
+		a.emit(u)
+	}
+	a.mutex.Unlock()
+}
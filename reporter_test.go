@@ -0,0 +1,91 @@
+package part5
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/pascaldekloe/part5/info"
+)
+
+func TestAnalogReporter(t *testing.T) {
+	var sys info.System[info.OrigAddr0, info.ComAddr8, info.ObjAddr16]
+	r := testExchange().Report()
+
+	now := time.Date(2024, 2, 29, 13, 14, 15, 0, time.UTC)
+	var buf bytes.Buffer
+	logger := NewLogger(sys, &buf)
+	reporter := NewAnalogReporter(r, 0.5, 10*time.Second, func() time.Time { return now }, func(u info.DataUnit[info.OrigAddr0, info.ComAddr8, info.ObjAddr16]) {
+		if err := MonitorDataUnit(logger, u); err != nil {
+			t.Error("emit got monitor error:", err)
+		}
+	})
+
+	update := func(n uint, value float32) {
+		t.Helper()
+		if err := reporter.Update(sys.MustObjAddrN(n), value, info.OK); err != nil {
+			t.Fatal("update error:", err)
+		}
+	}
+	update(1, 10)   // first: spont
+	update(1, 10.2) // within deadband
+	update(2, 5)    // first: spont
+	reporter.Cycle()
+	now = now.Add(5 * time.Second)
+	update(1, 11) // beyond deadband: spont
+	now = now.Add(5 * time.Second)
+	update(2, 5.25)  // within deadband
+	reporter.Cycle() // address 2 due
+	now = now.Add(5 * time.Second)
+	reporter.Cycle() // address 1 due
+	reporter.Cycle() // none due
+	now = now.Add(10 * time.Second)
+	reporter.Cycle() // both due
+
+	const want = "M_ME_NC_1 spont 00 07/00:01 10 []\n" +
+		"M_ME_NC_1 spont 00 07/00:02 5 []\n" +
+		"M_ME_NC_1 spont 00 07/00:01 11 []\n" +
+		"M_ME_NC_1 cyclic 00 07/00:02 5.25 []\n" +
+		"M_ME_NC_1 cyclic 00 07/00:01 11 []\n" +
+		"M_ME_NC_1 cyclic 00 07/00:01 11 []\n" +
+		"M_ME_NC_1 cyclic 00 07/00:02 5.25 []\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got reports:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+// Concurrent reports must not regress to older values.
+func TestAnalogReporterOrder(t *testing.T) {
+	var sys info.System[info.OrigAddr0, info.ComAddr8, info.ObjAddr16]
+	r := testExchange().Report()
+
+	var last float32
+	reporter := NewAnalogReporter(r, 0.5, 0, nil, func(u info.DataUnit[info.OrigAddr0, info.ComAddr8, info.ObjAddr16]) {
+		value := math.Float32frombits(binary.LittleEndian.Uint32(u.Info[2:]))
+		if value < last {
+			t.Errorf("%s reported %g after %g", u.Cause, value, last)
+		}
+		last = value
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 1; i <= 1000; i++ {
+			if err := reporter.Update(sys.MustObjAddrN(1), float32(i), info.OK); err != nil {
+				t.Error("update error:", err)
+				return
+			}
+		}
+	}()
+	for {
+		select {
+		case <-done:
+			return
+		default:
+			reporter.Cycle()
+		}
+	}
+}
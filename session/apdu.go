@@ -14,6 +14,12 @@ var (
 	errLength = errors.New("part5: APDU length out of range")
 )
 
+// APDUSize returns the number of octets of an APDU with the ASDU, i.e., the
+// WireSize plus the 6 octet APCI. Note that info.DataUnit implements WireSize.
+func APDUSize(asdu interface{ WireSize() int }) int {
+	return asdu.WireSize() + 6
+}
+
 // APDU (Application Protocol Data Unit) is a transport layer datagram.
 // The first 6 octets contain the APCI (Application Protocol Control
 // Information) header. The following ASDU (Application Service Data Unit)
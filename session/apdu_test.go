@@ -8,6 +8,8 @@ import (
 	"strings"
 	"testing"
 	"testing/iotest"
+
+	"github.com/pascaldekloe/part5/info"
 )
 
 // TestUFormat tests all 6 function compositions.
@@ -159,3 +161,21 @@ func TestUnmarshals(t *testing.T) {
 		}
 	}
 }
+
+func TestAPDUSize(t *testing.T) {
+	var sys info.System[info.OrigAddr8, info.ComAddr16, info.ObjAddr24]
+	u := sys.NewDataUnit()
+	u.Type = info.M_SP_NA_1
+	u.Enc = 1
+	u.Cause = info.Spont
+	u.Addr = sys.MustComAddrN(1)
+	u.Info = append(u.Info, 1, 2, 3, 1)
+
+	datagram, err := packASDU(u.Append(nil), 0, 0)
+	if err != nil {
+		t.Fatal("ASDU wrap error:", err)
+	}
+	if got, want := APDUSize(u), int(datagram[1])+2; got != want {
+		t.Errorf("got APDU size %d, want %d", got, want)
+	}
+}
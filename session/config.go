@@ -14,7 +14,8 @@ const (
 // The default is applied for each unspecified value.
 type TCPConfig struct {
 	// Maximum amount of time for TCP connection establishment. The standard
-	// specifies "t₀" in [1, 255] seconds with a default of 30.
+	// specifies "t₀" in [1, 255] seconds with a default of 30. Only Dial
+	// can apply the timeout, as TCP receives an established connection.
 	ConnectTimeout time.Duration
 
 	// Upper limit for the number of I-frames send without reception of a
@@ -48,6 +49,36 @@ type TCPConfig struct {
 	// set to 20 seconds.
 	// See chapter 5.2 of companion standard 104.
 	IdleTimeout time.Duration
+
+	// Maximum amount of time for the reception of an APDU once its start
+	// octet arrived. On expiry the connection is closed immediately. Zero
+	// disables the read deadline. Peers which stall halfway a frame are
+	// caught by SendUnackTimeout otherwise, yet only when the connection
+	// reports temporary errors.
+	FrameTimeout time.Duration
+
+	// LevelExitOnly omits the propagation of Down and Up on the Level
+	// channel of Station, such that Level needs no reading. The channel
+	// still closes on Exit. The session manages data transfer towards the
+	// Target level regardless. See Station CurrentLevel for status.
+	LevelExitOnly bool
+
+	// AutoStart issues STARTDT once connected, as if Up was send to the
+	// Target channel of Station. Only the controlling station [client]
+	// should start data transfer. The default leaves the session Down.
+	AutoStart bool
+
+	// StrictStart reports I-frames which arrive before data transfer is
+	// started with STARTDT on the Err channel of Transport. Such frames
+	// are discarded regardless. The default is a silent discard.
+	StrictStart bool
+
+	// TraceFunc gets called for each frame on the wire, when not nil. The
+	// summary is a compact description, and raw has the APDU serial, which
+	// is only valid until the function returns. Invocation may happen from
+	// multiple routines concurrently. The log package is used instead when
+	// Trace is set and TraceFunc is nil.
+	TraceFunc func(dir Direction, summary string, raw []byte)
 }
 
 // Check applies the default (defined by IEC) for each unspecified value.
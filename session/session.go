@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -16,9 +18,29 @@ var (
 	ErrNoConn = errors.New("part5: no connection")
 )
 
-// Trace activates wire logging.
+// Trace activates wire logging. See TCPConfig TraceFunc for an alternative.
 var Trace = false
 
+// Direction of transmission.
+type Direction uint
+
+const (
+	Receive Direction = iota // inbound
+	Send                     // outbound
+)
+
+// String returns a name.
+func (d Direction) String() string {
+	switch d {
+	case Receive:
+		return "inbound"
+	case Send:
+		return "outbound"
+	default:
+		return fmt.Sprintf("direction%+d", d)
+	}
+}
+
 // Level is the availability status.
 type Level uint
 
@@ -51,12 +73,48 @@ type Station struct {
 
 	// Level propagates status changes and must be read or
 	// operation blocks and may behave in an unexpected way.
+	// See TCPConfig LevelExitOnly for an alternative.
 	Level <-chan Level
 
 	// Target sets the desired availability level. Closing the channel is
 	// equivalent to Exit. Failure to reach the level causes the connection
 	// to terminate.
 	Target chan<- Level
+
+	// routines of the implementation, if any
+	routines *sync.WaitGroup
+	// level of the implementation, if any
+	levelNow *atomic.Uint32
+	// unacknowledged outbound of the implementation, if any
+	pendingOut func() (count int, oldest time.Duration)
+}
+
+// CurrentLevel returns the last level send on the Level channel. The read of
+// Level may lag behind.
+func (s *Station) CurrentLevel() Level {
+	if s.levelNow == nil {
+		return Exit
+	}
+	return Level(s.levelNow.Load())
+}
+
+// PendingOut returns the number of outbound I-frames which await their
+// acknowledgement, plus the time since the oldest one was sent. Both are zero
+// when none are pending, including after Exit.
+func (s *Station) PendingOut() (count int, oldest time.Duration) {
+	if s.pendingOut == nil {
+		return 0, 0
+	}
+	return s.pendingOut()
+}
+
+// WaitClosed blocks until all routines of the Station have exited. Exit alone
+// is not sufficient, as Class1 and Class2 get drained with ErrNoConn until the
+// user closes them.
+func (s *Station) WaitClosed() {
+	if s.routines != nil {
+		s.routines.Wait()
+	}
 }
 
 // Transport layer as datagram channels.
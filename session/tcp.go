@@ -5,7 +5,10 @@ import (
 	"io"
 	"log"
 	"net"
+	"os"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -26,7 +29,9 @@ var (
 	errBringUpExpire   = errors.New("part5: fatal STARTDT acknowledge timeout t₁")
 	errBringDownExpire = errors.New("part5: fatal STOPDT acknowledge timeout t₁")
 	errKeepAliveExpire = errors.New("part5: fatal TESTFR acknowledge timeout t₁")
+	errFrameExpire     = errors.New("part5: fatal APDU reception timeout")
 	errIllegalFunc     = errors.New("part5: illegal function ignored")
+	errNotStarted      = errors.New("part5: I-frame before STARTDT ignored")
 )
 
 type tcp struct {
@@ -41,6 +46,10 @@ type tcp struct {
 	// Station counterparts
 	level  chan<- Level
 	target <-chan Level
+	// last level send, for Station CurrentLevel
+	levelNow atomic.Uint32
+	// unacknowledged outbound, for Station PendingOut
+	outPending atomic.Pointer[outStatus]
 
 	recv chan apdu // for recvLoop
 	send chan apdu // for sendLoop
@@ -60,6 +69,8 @@ type tcp struct {
 	}
 
 	idleSince time.Time
+
+	routines sync.WaitGroup // see Station WaitClosed
 }
 
 // TCP returns a session with status Down.
@@ -73,7 +84,7 @@ func TCP(config TCPConfig, conn net.Conn) *Station {
 	targetChan := make(chan Level)
 	levelChan := make(chan Level)
 
-	t := tcp{
+	t := &tcp{
 		TCPConfig: config,
 		conn:      conn,
 		level:     levelChan,
@@ -91,6 +102,10 @@ func TCP(config TCPConfig, conn net.Conn) *Station {
 		idleSince: time.Now(),
 	}
 
+	// Down before any of the routines runs
+	t.levelNow.Store(uint32(Down))
+
+	t.routines.Add(3)
 	go t.recvLoop()
 	go t.sendLoop()
 	go t.run()
@@ -106,16 +121,43 @@ func TCP(config TCPConfig, conn net.Conn) *Station {
 		Addr:      conn.RemoteAddr(),
 		Level:     levelChan,
 		Target:    targetChan,
+		routines:  &t.routines,
+		levelNow:  &t.levelNow,
+		pendingOut: func() (int, time.Duration) {
+			p := t.outPending.Load()
+			if p == nil || p.count == 0 {
+				return 0, 0
+			}
+			return p.count, time.Since(p.oldest)
+		},
 	}
 }
 
+// Dialer is a template for Dial, which tests may hook into.
+var dialer net.Dialer
+
+// Dial connects to the TCP address, bound by ConnectTimeout "t₀" from config,
+// and it returns a session with status Down on success. The address syntax is
+// conform net.Dial.
+func Dial(config TCPConfig, addr string) (*Station, error) {
+	config.check()
+	d := dialer
+	d.Timeout = config.ConnectTimeout
+	conn, err := d.Dial("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	return TCP(config, conn), nil
+}
+
 // RecvLoop feeds t.recv.
 func (t *tcp) recvLoop() {
+	defer t.routines.Done()
 	defer close(t.recv)
 
 	var datagram apdu // reusable instance
 	for {
-		byteCount, err := datagram.Unmarshal(t.conn, 0)
+		byteCount, err := t.unmarshal(&datagram)
 
 		var deadline time.Time
 		for err != nil {
@@ -143,15 +185,36 @@ func (t *tcp) recvLoop() {
 			byteCount, err = datagram.Unmarshal(t.conn, byteCount)
 		}
 
-		if Trace {
-			log.Printf("%s@%s: received %s", t.conn.RemoteAddr(), t.conn.LocalAddr(), datagram.String())
-		}
+		t.trace(Receive, &datagram)
 		t.recv <- datagram // copy
 	}
 }
 
+// Unmarshal reads the next APDU, with FrameTimeout applied when set.
+func (t *tcp) unmarshal(datagram *apdu) (byteCount int, err error) {
+	if t.FrameTimeout == 0 {
+		return datagram.Unmarshal(t.conn, 0)
+	}
+
+	// await start without deadline
+	byteCount, err = io.ReadFull(t.conn, datagram[:1])
+	if err != nil {
+		return byteCount, err
+	}
+
+	t.conn.SetReadDeadline(time.Now().Add(t.FrameTimeout))
+	byteCount, err = datagram.Unmarshal(t.conn, byteCount)
+	if errors.Is(err, os.ErrDeadlineExceeded) {
+		// not recoverable, despite the Temporary flag
+		return byteCount, errFrameExpire
+	}
+	t.conn.SetReadDeadline(time.Time{})
+	return byteCount, err
+}
+
 // SendLoop drains t.send.
 func (t *tcp) sendLoop() {
+	defer t.routines.Done()
 	defer close(t.sendQuit)
 
 	for datagram := range t.send {
@@ -171,17 +234,41 @@ func (t *tcp) sendLoop() {
 
 			byteCount, err = datagram.Marshal(t.conn, byteCount)
 		}
-		if Trace {
-			log.Printf("%s@%s: send %s", t.conn.RemoteAddr(), t.conn.LocalAddr(), datagram.String())
-		}
+		t.trace(Send, &datagram)
+	}
+}
+
+// Trace reports a datagram on the wire, if enabled.
+func (t *tcp) trace(dir Direction, datagram *apdu) {
+	switch {
+	case t.TraceFunc != nil:
+		t.TraceFunc(dir, datagram.String(), datagram[:int(datagram[1])+2])
+	case Trace && dir == Receive:
+		log.Printf("%s@%s: received %s", t.conn.RemoteAddr(), t.conn.LocalAddr(), datagram.String())
+	case Trace:
+		log.Printf("%s@%s: send %s", t.conn.RemoteAddr(), t.conn.LocalAddr(), datagram.String())
+	}
+}
+
+// SetLevel propagates a status change.
+func (t *tcp) setLevel(l Level) {
+	t.levelNow.Store(uint32(l))
+	if !t.LevelExitOnly {
+		t.level <- l
 	}
 }
 
 // Run is the big fat state machine.
 func (t *tcp) run() {
+	defer t.routines.Done()
+
 	// connected and no "data transfer" yet
 	level := Down
-	t.level <- level
+	t.setLevel(level)
+	if t.AutoStart {
+		t.send <- newFunc(bringUp)
+		t.idleSince = time.Now()
+	}
 
 	checkTicker := time.NewTicker(timeoutResolution)
 
@@ -196,7 +283,7 @@ func (t *tcp) run() {
 			default:
 				break // best effort
 			}
-			t.level <- Down
+			t.setLevel(Down)
 		}
 
 		if t.ackNoIn != t.seqNoIn {
@@ -223,18 +310,23 @@ func (t *tcp) run() {
 		}
 
 		// report to API
+		t.levelNow.Store(uint32(Exit))
 		close(t.level) // sends Exit [0] level
 		for i := t.ackNoOut; i != t.seqNoOut; i++ {
 			t.pending[i].done <- ErrConnLost
 		}
+		t.outPending.Store(nil)
 		close(t.in)
 		close(t.err)
+		t.routines.Add(2)
 		go func() {
+			defer t.routines.Done()
 			for o := range t.class1 {
 				o.err <- ErrNoConn
 			}
 		}()
 		go func() {
+			defer t.routines.Done()
 			for o := range t.class2 {
 				o.err <- ErrNoConn
 			}
@@ -363,6 +455,9 @@ func (t *tcp) run() {
 
 			case iFrame:
 				if level < Up {
+					if t.StrictStart {
+						t.err <- errNotStarted
+					}
 					break // discard
 				}
 
@@ -384,33 +479,31 @@ func (t *tcp) run() {
 				t.seqNoIn = (t.seqNoIn + 1) & 32767
 
 				if seqNoCount(t.ackNoIn, t.seqNoIn) >= t.RecvUnackMax {
-					t.send <- newAck(t.seqNoIn)
-					t.ackNoIn = t.seqNoIn
-					t.idleSince = time.Now()
+					t.ack(level)
 				}
 
 			case uFrame:
 				switch datagram.Function() {
 				case bringUp:
 					level = Up
-					t.level <- level
+					t.setLevel(level)
 					t.send <- newFunc(bringUpOK)
 					t.idleSince = time.Now()
 
 				case bringUpOK:
 					level = Up
-					t.level <- level
+					t.setLevel(level)
 					bringUpSend = willNotTimeout
 
 				case bringDown:
 					level = Down
-					t.level <- level
+					t.setLevel(level)
 					t.send <- newFunc(bringDownOK)
 					t.idleSince = time.Now()
 
 				case bringDownOK:
 					level = Down
-					t.level <- level
+					t.setLevel(level)
 					bringDownSend = willNotTimeout
 
 				case keepAlive:
@@ -443,10 +536,61 @@ func (t *tcp) submit(o *Outbound) {
 	p.done = o.err
 	p.send = time.Now()
 
+	t.publishPending()
+
 	t.send <- datagram
 	t.idleSince = time.Now()
 }
 
+// Ack confirms all inbound I-frames. Any outbound I-frame available carries the
+// acknowledgement instead of an S-frame, when the send window permits.
+func (t *tcp) ack(level Level) {
+	if level >= Up && seqNoCount(t.ackNoOut, t.seqNoOut) <= t.SendUnackMax {
+		select {
+		case o, ok := <-t.class1:
+			if ok {
+				t.submit(o)
+			}
+		default:
+			select {
+			case o, ok := <-t.class2:
+				if ok {
+					t.submit(o)
+				}
+			default:
+				break // nothing available right now
+			}
+		}
+		if t.ackNoIn == t.seqNoIn {
+			return // coalesced
+		}
+	}
+
+	t.send <- newAck(t.seqNoIn)
+	t.ackNoIn = t.seqNoIn
+	t.idleSince = time.Now()
+}
+
+// OutStatus is a snapshot of the unacknowledged outbound.
+type outStatus struct {
+	count  int
+	oldest time.Time // send time
+}
+
+// PublishPending updates the status for Station PendingOut. The count and the
+// send time are swapped as one, such that readers can't mix them up.
+func (t *tcp) publishPending() {
+	n := int(seqNoCount(t.ackNoOut, t.seqNoOut))
+	if n == 0 {
+		t.outPending.Store(nil)
+		return
+	}
+	t.outPending.Store(&outStatus{
+		count:  n,
+		oldest: t.pending[t.ackNoOut&32767].send,
+	})
+}
+
 func (t *tcp) updateAckNoOut(n uint) (ok bool) {
 	last := t.ackNoOut
 	if n == last {
@@ -469,6 +613,7 @@ func (t *tcp) updateAckNoOut(n uint) (ok bool) {
 	}
 
 	t.ackNoOut = n
+	t.publishPending()
 	return true
 }
 
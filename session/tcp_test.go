@@ -2,11 +2,13 @@ package session
 
 import (
 	"bytes"
+	"context"
 	"io"
 	"log"
 	"net"
 	"strings"
 	"sync"
+	"syscall"
 	"testing"
 	"time"
 )
@@ -161,6 +163,399 @@ func TestBringDown(t *testing.T) {
 	}
 }
 
+func TestCurrentLevel(t *testing.T) {
+	connA, connB := net.Pipe()
+	a, b, exitGroup := newTCPTestDuo(t, connA, connB, TCPConfig{})
+
+	if got := a.CurrentLevel(); got != Up {
+		t.Errorf("station A got current level %s after STARTDT, want %s", got, Up)
+	}
+	if got := b.CurrentLevel(); got != Up {
+		t.Errorf("station B got current level %s after STARTDT, want %s", got, Up)
+	}
+
+	close(a.Target) // exit
+	exitGroup.Wait()
+	if got := a.CurrentLevel(); got != Exit {
+		t.Errorf("station A got current level %s after exit, want %s", got, Exit)
+	}
+}
+
+// Consumers may ignore Level with LevelExitOnly.
+func TestLevelExitOnly(t *testing.T) {
+	connA, connB := net.Pipe()
+	config := TCPConfig{LevelExitOnly: true}
+	a := TCP(config, connA)
+	b := TCP(config, connB)
+	if got := a.CurrentLevel(); got != Down {
+		t.Errorf("station A got current level %s before STARTDT, want %s", got, Down)
+	}
+	go func() {
+		for err := range a.Err {
+			t.Log("station A error:", err)
+		}
+	}()
+	go func() {
+		for err := range b.Err {
+			t.Log("station B error:", err)
+		}
+	}()
+
+	a.Target <- Up
+	o := NewOutbound([]byte("ping"))
+	select {
+	case a.Class1 <- o:
+		break
+	case <-time.After(time.Second):
+		t.Fatal("outbound blocked")
+	}
+	if got := <-b.In; string(got) != "ping" {
+		t.Errorf("station B got inbound %q, want %q", got, "ping")
+	}
+	if err := <-o.Done; err != nil {
+		t.Error("outbound got error:", err)
+	}
+	if got := b.CurrentLevel(); got != Up {
+		t.Errorf("station B got current level %s, want %s", got, Up)
+	}
+
+	close(a.Target)
+	for _, s := range []*Station{a, b} {
+		if l, ok := <-s.Level; ok {
+			t.Errorf("got level %s, want channel close only", l)
+		}
+		close(s.Class1)
+		close(s.Class2)
+		s.WaitClosed()
+	}
+}
+
+func TestTraceFunc(t *testing.T) {
+	var mutex sync.Mutex
+	got := make(map[string]bool)
+	stopped := make(chan struct{}) // STOPDT_CON at station A
+	config := TCPConfig{
+		TraceFunc: func(dir Direction, summary string, raw []byte) {
+			if len(raw) < 6 || raw[0] != start {
+				t.Errorf("%s %s got raw serial %#x", dir, summary, raw)
+			}
+			mutex.Lock()
+			defer mutex.Unlock()
+			key := dir.String() + " " + summary
+			if key == "inbound U[STOPDT_CON]" && !got[key] {
+				close(stopped)
+			}
+			got[key] = true
+		},
+	}
+
+	connA, connB := net.Pipe()
+	a, _, exitGroup := newTCPTestDuo(t, connA, connB, config)
+	a.Target <- Down
+	select {
+	case <-stopped:
+		break
+	case <-time.After(time.Second):
+		t.Error("STOPDT_CON not received")
+	}
+	a.Target <- Exit
+	exitGroup.Wait()
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	for _, want := range []string{
+		"outbound U[STARTDT_ACT]",
+		"inbound U[STARTDT_ACT]",
+		"outbound U[STARTDT_CON]",
+		"inbound U[STARTDT_CON]",
+		"outbound U[STOPDT_ACT]",
+		"inbound U[STOPDT_ACT]",
+		"outbound U[STOPDT_CON]",
+		"inbound U[STOPDT_CON]",
+	} {
+		if !got[want] {
+			t.Errorf("trace %q not seen", want)
+		}
+	}
+}
+
+// A peer which stalls halfway a frame is abandoned on FrameTimeout.
+func TestFrameTimeout(t *testing.T) {
+	connA, connB := net.Pipe()
+	defer connB.Close()
+	a := TCP(TCPConfig{FrameTimeout: 100 * time.Millisecond}, connA)
+	if l := <-a.Level; l != Down {
+		t.Fatalf("got initial level %s, want %s", l, Down)
+	}
+
+	// header only
+	if _, err := connB.Write([]byte{start, 4}); err != nil {
+		t.Fatal("peer write error:", err)
+	}
+
+	select {
+	case err := <-a.Err:
+		if err != errFrameExpire {
+			t.Errorf("got error %v, want %v", err, errFrameExpire)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("frame timeout not applied")
+	}
+
+	select {
+	case l, ok := <-a.Level:
+		if ok {
+			t.Errorf("got level %s, want Exit", l)
+		}
+	case <-time.After(time.Second):
+		t.Error("station did not exit")
+	}
+}
+
+func TestAutoStart(t *testing.T) {
+	connA, connB := net.Pipe()
+	a := TCP(TCPConfig{AutoStart: true}, connA)
+	b := TCP(TCPConfig{}, connB)
+	for _, s := range []*Station{a, b} {
+		go func(s *Station) {
+			for err := range s.Err {
+				t.Log("station error:", err)
+			}
+		}(s)
+	}
+
+	peerLevels := make(chan Level, 8)
+	go func() {
+		for l := range b.Level {
+			peerLevels <- l
+		}
+		close(peerLevels)
+	}()
+
+	for _, l := range []Level{Down, Up} {
+		select {
+		case got := <-a.Level:
+			if got != l {
+				t.Fatalf("got level %s, want %s", got, l)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("level %s not reached", l)
+		}
+	}
+
+	close(a.Target)
+	for range a.Level {
+	}
+	var got []Level
+	for l := range peerLevels {
+		got = append(got, l)
+	}
+	if len(got) < 2 || got[0] != Down || got[1] != Up {
+		t.Errorf("peer got levels %s, want Down and Up first", got)
+	}
+	for _, s := range []*Station{a, b} {
+		close(s.Class1)
+		close(s.Class2)
+		s.WaitClosed()
+	}
+}
+
+func TestStrictStart(t *testing.T) {
+	connA, connB := net.Pipe()
+	defer connB.Close()
+	a := TCP(TCPConfig{StrictStart: true}, connA)
+	if l := <-a.Level; l != Down {
+		t.Fatalf("got initial level %s, want %s", l, Down)
+	}
+
+	// I-frame with sequence numbers zero, without STARTDT
+	if _, err := connB.Write([]byte{start, 8, 0, 0, 0, 0, 'p', 'i', 'n', 'g'}); err != nil {
+		t.Fatal("peer write error:", err)
+	}
+
+	select {
+	case err := <-a.Err:
+		if err != errNotStarted {
+			t.Errorf("got error %v, want %v", err, errNotStarted)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("premature I-frame not reported")
+	}
+	select {
+	case payload := <-a.In:
+		t.Errorf("got inbound %q before STARTDT", payload)
+	default:
+		break
+	}
+
+	close(a.Class1)
+	close(a.Class2)
+}
+
+func TestPendingOut(t *testing.T) {
+	connA, connB := net.Pipe()
+	defer connB.Close()
+	a := TCP(TCPConfig{}, connA)
+	go func() {
+		for err := range a.Err {
+			t.Log("station error:", err)
+		}
+	}()
+	if l := <-a.Level; l != Down {
+		t.Fatalf("got initial level %s, want %s", l, Down)
+	}
+
+	// peer confirms STARTDT
+	a.Target <- Up
+	buf := make([]byte, 6)
+	if _, err := io.ReadFull(connB, buf); err != nil {
+		t.Fatal("peer read error:", err)
+	}
+	if _, err := connB.Write([]byte{start, 4, 0x0b, 0, 0, 0}); err != nil {
+		t.Fatal("peer write error:", err)
+	}
+	if l := <-a.Level; l != Up {
+		t.Fatalf("got level %s, want %s", l, Up)
+	}
+
+	if n, oldest := a.PendingOut(); n != 0 || oldest != 0 {
+		t.Errorf("got %d pending since %s before send, want none", n, oldest)
+	}
+
+	// peer reads without acknowledgement
+	for i := 0; i < 3; i++ {
+		a.Class1 <- NewOutbound([]byte("ping"))
+		buf := make([]byte, 6+4)
+		if _, err := io.ReadFull(connB, buf); err != nil {
+			t.Fatal("peer read error:", err)
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+	n, oldest := a.PendingOut()
+	if n != 3 || oldest < 10*time.Millisecond || oldest > time.Second {
+		t.Errorf("got %d pending since %s, want 3 since about 10 ms", n, oldest)
+	}
+
+	close(a.Class1)
+	close(a.Class2)
+	connB.Close()
+	for range a.Level {
+	}
+	a.WaitClosed()
+	if n, _ := a.PendingOut(); n != 0 {
+		t.Errorf("got %d pending after exit, want 0", n)
+	}
+}
+
+func TestDialTimeout(t *testing.T) {
+	// connection establishment never completes
+	defer func(d net.Dialer) { dialer = d }(dialer)
+	dialer.ControlContext = func(ctx context.Context, _, _ string, _ syscall.RawConn) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	const timeout = time.Second
+	start := time.Now()
+	st, err := Dial(TCPConfig{ConnectTimeout: timeout}, "127.0.0.1:2404")
+	elapsed := time.Since(start)
+	if err == nil {
+		st.Target <- Exit
+		t.Fatal("dial of non-accepting address got no error")
+	}
+	if e, ok := err.(net.Error); !ok || !e.Timeout() {
+		t.Errorf("got error %v, want a timeout", err)
+	}
+	if elapsed < timeout || elapsed > timeout+timeout/2 {
+		t.Errorf("dial took %s with ConnectTimeout of %s", elapsed, timeout)
+	}
+}
+
+// A full connect–exit cycle leaves no routines behind.
+func TestWaitClosed(t *testing.T) {
+	connA, connB := net.Pipe()
+	a, b, exitGroup := newTCPTestDuo(t, connA, connB, TCPConfig{})
+	a.Target <- Exit
+	exitGroup.Wait()
+
+	for _, st := range []*Station{a, b} {
+		close(st.Class1)
+		close(st.Class2)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		a.WaitClosed()
+		b.WaitClosed()
+	}()
+	select {
+	case <-done:
+		break
+	case <-time.After(time.Second):
+		t.Error("routines did not exit")
+	}
+}
+
+// Acknowledgement must not exceed the RecvUnackMax "w" window.
+func TestAckWindow(t *testing.T) {
+	const w, n = 4, 40
+
+	// receive sequence numbers from S-frames in order of arrival
+	var mutex sync.Mutex
+	var acks []uint
+
+	connA, connB := net.Pipe()
+	a, b, exitGroup := newTCPTestDuo(t, connA, connB, TCPConfig{
+		RecvUnackMax:     w,
+		RecvUnackTimeout: time.Second,
+		TraceFunc: func(dir Direction, _ string, raw []byte) {
+			// only station A receives S-frames
+			if dir == Receive && len(raw) == 6 && raw[2]&3 == 1 {
+				mutex.Lock()
+				acks = append(acks, uint(raw[4]>>1)|uint(raw[5])<<7)
+				mutex.Unlock()
+			}
+		},
+	})
+	defer func() {
+		a.Target <- Exit
+		exitGroup.Wait()
+	}()
+	go func() {
+		for range b.In {
+			continue // discard
+		}
+	}()
+
+	outs := make([]*Outbound, n)
+	for i := range outs {
+		outs[i] = NewOutbound([]byte{byte(i)})
+		a.Class1 <- outs[i]
+	}
+	for i, o := range outs {
+		if err := <-o.Done; err != nil {
+			t.Errorf("outbound %d got error: %s", i, err)
+		}
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	if len(acks) < n/w {
+		t.Errorf("got %d S-frames for %d I-frames, want at least %d", len(acks), n, n/w)
+	}
+	var last uint
+	for _, ack := range acks {
+		if ack-last > w {
+			t.Errorf("S-frame acknowledged I-frames %d up to %d, want at most %d at once", last, ack, w)
+		}
+		last = ack
+	}
+	if last != n {
+		t.Errorf("last S-frame acknowledged up to %d, want %d", last, n)
+	}
+}
+
 func TestUnackThreashold(t *testing.T) {
 	connA, connB := net.Pipe()
 	a, b, exitGroup := newTCPTestDuo(t, connA, connB, TCPConfig{
@@ -3,6 +3,7 @@ package part5
 import (
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/pascaldekloe/part5/info"
 )
@@ -248,6 +249,149 @@ func (del *MonitorDelegate[Orig, Com, Obj]) InitEnd(u info.DataUnit[Orig, Com, O
 	}
 }
 
+// ArrivalTagger passes information without a time tag as its time-tagged
+// equivalent with the arrival time as info.CP56Time2a, i.e., calls to SinglePt
+// become calls to SinglePtAtMoment, and so on. The info.DataUnit keeps the type
+// identifier as received. All other calls pass as is.
+type arrivalTagger[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr] struct {
+	Monitor[Orig, Com, Obj]
+	clock func() time.Time
+}
+
+// NewArrivalTagger returns a Monitor which forwards to next with the arrival
+// time on information without a time tag. The clock defaults to time.Now when
+// nil. Note that the time-zone of clock must match the time-zone used by next,
+// as the info.CP56Time2a encoding loses such context.
+func NewArrivalTagger[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr](next Monitor[Orig, Com, Obj], clock func() time.Time) Monitor[Orig, Com, Obj] {
+	if clock == nil {
+		clock = time.Now
+	}
+	return arrivalTagger[Orig, Com, Obj]{next, clock}
+}
+
+func (tagger arrivalTagger[Orig, Com, Obj]) now() info.CP56Time2a {
+	var tag info.CP56Time2a
+	tag.Set(tagger.clock())
+	return tag
+}
+
+func (tagger arrivalTagger[Orig, Com, Obj]) SinglePt(u info.DataUnit[Orig, Com, Obj], addr Obj, p info.SinglePtQual) {
+	tagger.Monitor.SinglePtAtMoment(u, addr, p, tagger.now())
+}
+
+func (tagger arrivalTagger[Orig, Com, Obj]) DoublePt(u info.DataUnit[Orig, Com, Obj], addr Obj, p info.DoublePtQual) {
+	tagger.Monitor.DoublePtAtMoment(u, addr, p, tagger.now())
+}
+
+func (tagger arrivalTagger[Orig, Com, Obj]) Step(u info.DataUnit[Orig, Com, Obj], addr Obj, p info.StepQual) {
+	tagger.Monitor.StepAtMoment(u, addr, p, tagger.now())
+}
+
+func (tagger arrivalTagger[Orig, Com, Obj]) Bits(u info.DataUnit[Orig, Com, Obj], addr Obj, b info.BitsQual) {
+	tagger.Monitor.BitsAtMoment(u, addr, b, tagger.now())
+}
+
+func (tagger arrivalTagger[Orig, Com, Obj]) NormUnqual(u info.DataUnit[Orig, Com, Obj], addr Obj, n info.Norm) {
+	// quality descriptor zero is OK
+	tagger.Monitor.NormAtMoment(u, addr, info.NormQual{n[0], n[1], 0}, tagger.now())
+}
+
+func (tagger arrivalTagger[Orig, Com, Obj]) Norm(u info.DataUnit[Orig, Com, Obj], addr Obj, n info.NormQual) {
+	tagger.Monitor.NormAtMoment(u, addr, n, tagger.now())
+}
+
+func (tagger arrivalTagger[Orig, Com, Obj]) Scaled(u info.DataUnit[Orig, Com, Obj], addr Obj, v int16, q info.Qual) {
+	tagger.Monitor.ScaledAtMoment(u, addr, v, q, tagger.now())
+}
+
+func (tagger arrivalTagger[Orig, Com, Obj]) Float(u info.DataUnit[Orig, Com, Obj], addr Obj, f float32, q info.Qual) {
+	tagger.Monitor.FloatAtMoment(u, addr, f, q, tagger.now())
+}
+
+func (tagger arrivalTagger[Orig, Com, Obj]) Totals(u info.DataUnit[Orig, Com, Obj], addr Obj, c info.Counter) {
+	tagger.Monitor.TotalsAtMoment(u, addr, c, tagger.now())
+}
+
+// Private implements the PrivateMonitor interface.
+func (tagger arrivalTagger[Orig, Com, Obj]) Private(u info.DataUnit[Orig, Com, Obj]) error {
+	return monitorPrivate(tagger.Monitor, u)
+}
+
+type privateMonitor[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr] struct {
+	Monitor[Orig, Com, Obj]
+	f func(info.DataUnit[Orig, Com, Obj])
+}
+
+// NewPrivate returns a Monitor which passes to f on each type identifier from
+// the private range 128..255, and to mon otherwise. The payload of units for f
+// is not validated in any way.
+func NewPrivate[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr](mon Monitor[Orig, Com, Obj], f func(info.DataUnit[Orig, Com, Obj])) Monitor[Orig, Com, Obj] {
+	return privateMonitor[Orig, Com, Obj]{mon, f}
+}
+
+// Private implements the PrivateMonitor interface.
+func (p privateMonitor[Orig, Com, Obj]) Private(u info.DataUnit[Orig, Com, Obj]) error {
+	p.f(u)
+	return nil
+}
+
+// ControlDelegate passes the Controller interface to any its sub-interfaces.
+// All fields are optional. Nil causes silent discards.
+type ControlDelegate[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr] struct {
+	SingleCmdController[Orig, Com, Obj]
+	DoubleCmdController[Orig, Com, Obj]
+	RegulCmdController[Orig, Com, Obj]
+	DelayAcqController[Orig, Com, Obj]
+	ClockSyncController[Orig, Com, Obj]
+}
+
+// NewControlDelegate returns a new delegate with each sub-interface nil.
+func NewControlDelegate[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr](_ info.System[Orig, Com, Obj]) *ControlDelegate[Orig, Com, Obj] {
+	return NewControlDelegateDefault[Orig, Com, Obj](nil)
+}
+
+// NewControlDelegateDefault returns a new delegate with each sub-interface set
+// to a def(ault) value. Note that def may be nil.
+func NewControlDelegateDefault[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr](def Controller[Orig, Com, Obj]) *ControlDelegate[Orig, Com, Obj] {
+	return &ControlDelegate[Orig, Com, Obj]{
+		SingleCmdController: def,
+		DoubleCmdController: def,
+		RegulCmdController:  def,
+		DelayAcqController:  def,
+		ClockSyncController: def,
+	}
+}
+
+func (del *ControlDelegate[Orig, Com, Obj]) SingleCmd(u info.DataUnit[Orig, Com, Obj], addr Obj, pt info.SinglePt, q info.CmdQual) {
+	if del.SingleCmdController != nil {
+		del.SingleCmdController.SingleCmd(u, addr, pt, q)
+	}
+}
+
+func (del *ControlDelegate[Orig, Com, Obj]) DoubleCmd(u info.DataUnit[Orig, Com, Obj], addr Obj, pt info.DoublePt, q info.CmdQual) {
+	if del.DoubleCmdController != nil {
+		del.DoubleCmdController.DoubleCmd(u, addr, pt, q)
+	}
+}
+
+func (del *ControlDelegate[Orig, Com, Obj]) RegulCmd(u info.DataUnit[Orig, Com, Obj], addr Obj, r info.Regul, q info.CmdQual) {
+	if del.RegulCmdController != nil {
+		del.RegulCmdController.RegulCmd(u, addr, r, q)
+	}
+}
+
+func (del *ControlDelegate[Orig, Com, Obj]) DelayAcq(u info.DataUnit[Orig, Com, Obj], delay info.CP16Time2a) {
+	if del.DelayAcqController != nil {
+		del.DelayAcqController.DelayAcq(u, delay)
+	}
+}
+
+func (del *ControlDelegate[Orig, Com, Obj]) ClockSync(u info.DataUnit[Orig, Com, Obj], tag info.CP56Time2a) {
+	if del.ClockSyncController != nil {
+		del.ClockSyncController.ClockSync(u, tag)
+	}
+}
+
 type logger[Orig info.OrigAddr, Com info.ComAddr, Obj info.ObjAddr] struct {
 	W io.Writer
 }